@@ -0,0 +1,296 @@
+/*
+Copyright 2017 Google Inc. All rights reserved.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// The gotika command parses documents with a Tika Server.
+//
+// Usage:
+//
+//	gotika [OPTIONS] COMMAND [FILE|GLOB|-]...
+//
+// The commands are parse, meta, detect, language and unpack. Each FILE is
+// processed in turn; patterns like *.pdf are expanded, and - or no files at
+// all read a document from standard input. For example:
+//
+//	gotika -server_url http://localhost:9998 parse report.pdf
+//	gotika meta -json 'scans/*.tif'
+//	cat mail.eml | gotika unpack -out attachments
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/go-tika/tika"
+)
+
+// Commands.
+const (
+	parse    = "parse"
+	meta     = "meta"
+	detect   = "detect"
+	language = "language"
+	unpack   = "unpack"
+)
+
+// Command line flags.
+var (
+	serverURL = flag.String("server_url", defaultURL(), "URL of the Tika Server. The default is $TIKA_URL, if it is set.")
+	serverJAR = flag.String("server_jar", "", "Path to a Tika Server JAR. This will start a new server, ignoring -server_url.")
+	timeout   = flag.Duration("timeout", 0, "Maximum time to spend on each document, for example 30s. 0 means no limit.")
+	field     = flag.String("field", "", `Only print this metadata field, with the "meta" command.`)
+	asJSON    = flag.Bool("json", false, `Print metadata as JSON, with the "meta" command.`)
+	recursive = flag.Bool("recursive", false, `Print the content of every embedded document separately, with the "parse" command.`)
+	outDir    = flag.String("out", ".", `Directory to write embedded documents to, with the "unpack" command. With several inputs, each is unpacked to a subdirectory named after it.`)
+	all       = flag.Bool("all", false, `Also write the text and metadata of the container, with the "unpack" command.`)
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] COMMAND [FILE|GLOB|-]...\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "COMMANDS: parse, meta, detect, language, unpack\n\n")
+	fmt.Fprintf(os.Stderr, "With no FILE, or when FILE is -, read standard input.\n\n")
+	fmt.Fprintln(os.Stderr, "OPTIONS:")
+	flag.PrintDefaults()
+}
+
+func defaultURL() string {
+	if u := os.Getenv("TIKA_URL"); u != "" {
+		return u
+	}
+	return "http://localhost:9998"
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gotika: ")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cmd := flag.Arg(0)
+	// Allow flags after the command too, like "gotika meta -json doc.pdf".
+	flag.CommandLine.Parse(flag.Args()[1:])
+	switch cmd {
+	case parse, meta, detect, language, unpack:
+	default:
+		flag.Usage()
+		log.Fatalf("invalid command %q", cmd)
+	}
+	inputs, err := expand(flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *serverJAR != "" {
+		s, err := tika.NewServer(*serverJAR, "")
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := s.Start(context.Background()); err != nil {
+			log.Fatalf("could not start server: %v", err)
+		}
+		*serverURL = s.URL()
+		os.Exit(runAll(cmd, inputs, s.Stop))
+	}
+	os.Exit(runAll(cmd, inputs, nil))
+}
+
+// runAll runs cmd on every input, calls stop if it is not nil, and returns
+// the exit code.
+func runAll(cmd string, inputs []string, stop func() error) int {
+	c := tika.NewClient(nil, *serverURL)
+	code := 0
+	for _, in := range inputs {
+		if err := run(c, cmd, in, len(inputs) > 1); err != nil {
+			log.Printf("%s: %v", displayName(in), err)
+			code = 1
+		}
+	}
+	if stop != nil {
+		if err := stop(); err != nil {
+			log.Printf("could not stop server: %v", err)
+		}
+	}
+	return code
+}
+
+// expand returns the inputs named by args, expanding glob patterns. An empty
+// args means standard input, named "-".
+func expand(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{"-"}, nil
+	}
+	var inputs []string
+	for _, arg := range args {
+		if arg == "-" || !strings.ContainsAny(arg, "*?[") {
+			inputs = append(inputs, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", arg, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %q", arg)
+		}
+		inputs = append(inputs, matches...)
+	}
+	return inputs, nil
+}
+
+func displayName(in string) string {
+	if in == "-" {
+		return "<stdin>"
+	}
+	return in
+}
+
+// run runs cmd on the input named in. If several is set, the output is
+// labeled with the name of the input.
+func run(c *tika.Client, cmd, in string, several bool) error {
+	var r io.Reader = os.Stdin
+	if in != "-" {
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	ctx := context.Background()
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	switch cmd {
+	case detect, language:
+		var out string
+		var err error
+		if cmd == detect {
+			out, _, err = c.DetectReader(ctx, r, filepath.Base(in))
+		} else {
+			out, err = c.Language(ctx, r)
+		}
+		if err != nil {
+			return err
+		}
+		if several {
+			fmt.Printf("%s: %s\n", displayName(in), strings.TrimSpace(out))
+		} else {
+			fmt.Println(strings.TrimSpace(out))
+		}
+		return nil
+	case unpack:
+		dir := *outDir
+		if several {
+			dir = filepath.Join(dir, filepath.Base(in))
+		}
+		return unpackTo(ctx, c, r, dir)
+	}
+
+	out, err := textOutput(ctx, c, cmd, r)
+	if err != nil {
+		return err
+	}
+	if several {
+		fmt.Printf("==> %s <==\n", displayName(in))
+	}
+	fmt.Println(out)
+	return nil
+}
+
+// textOutput returns the output of the parse and meta commands for r.
+func textOutput(ctx context.Context, c *tika.Client, cmd string, r io.Reader) (string, error) {
+	if cmd == parse {
+		if !*recursive {
+			return c.Parse(ctx, r)
+		}
+		docs, err := c.ParseRecursive(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return strings.Join(docs, "\n"), nil
+	}
+	switch {
+	case *field != "" && *asJSON:
+		v, err := c.MetaFieldValues(ctx, r, *field)
+		if err != nil {
+			return "", err
+		}
+		return marshal(v)
+	case *field != "":
+		return c.MetaField(ctx, r, *field)
+	case *asJSON:
+		m, err := c.MetaJSON(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return marshal(m)
+	}
+	return c.Meta(ctx, r)
+}
+
+func marshal(v interface{}) (string, error) {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// unpackTo writes the documents embedded in r to dir.
+func unpackTo(ctx context.Context, c *tika.Client, r io.Reader, dir string) error {
+	unpackFunc := c.Unpack
+	if *all {
+		unpackFunc = c.UnpackAll
+	}
+	files, err := unpackFunc(ctx, r)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Don't let entry names like ../x escape dir.
+		rel := filepath.Clean(filepath.FromSlash(name))
+		if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("invalid embedded document name %q", name)
+		}
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, files[name], 0644); err != nil {
+			return err
+		}
+		fmt.Println(path)
+	}
+	return nil
+}
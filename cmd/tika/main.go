@@ -54,7 +54,7 @@ const (
 
 // Command line flags.
 var (
-	downloadVersion = flag.String("download_version", "", fmt.Sprintf("Tika Server JAR version to download. If -serverJAR is specified, it will be downloaded to that location, otherwise it will be downloaded to your working directory. If the JAR has already been downloaded and has the correct MD5, this will do nothing. Valid versions: %v.", tika.Versions))
+	downloadVersion = flag.String("download_version", "", fmt.Sprintf("Tika Server JAR version to download. If -serverJAR is specified, it will be downloaded to that location, otherwise it will be downloaded to your working directory. If the JAR has already been downloaded and has the correct SHA-512, this will do nothing. Valid versions: %v.", tika.Versions))
 	filename        = flag.String("filename", "", "Path to file to parse.")
 	metaField       = flag.String("field", "", `Specific field to get when using the "meta" action. Undefined when using the -recursive flag.`)
 	recursive       = flag.Bool("recursive", false, `Whether to run "parse" or "meta" recursively, returning a list with one element per embedded document. Undefined when using the -field flag.`)
@@ -72,7 +72,7 @@ func main() {
 	action := flag.Arg(0)
 
 	if *downloadVersion != "" {
-		v := tika.Versions[len(tika.Versions) - 1]
+		v := tika.Versions[len(tika.Versions)-1]
 		supported := false
 		for _, sv := range tika.Versions {
 			if tika.Version(*downloadVersion) == sv {
@@ -85,7 +85,7 @@ func main() {
 			log.Fatalf("unsupported server version: %q", *downloadVersion)
 		}
 		if *serverJAR == "" {
-			*serverJAR = "tika-server-" + string(v) + ".jar"
+			*serverJAR = tika.ServerJARName(v)
 		}
 		if err := tika.DownloadServer(context.Background(), v, *serverJAR); err != nil {
 			log.Fatal(err)
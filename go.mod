@@ -1,5 +1,8 @@
 module github.com/google/go-tika
 
-go 1.11
+go 1.21
 
-require golang.org/x/net v0.0.0-20190522155817-f3200d17e092
+require (
+	golang.org/x/crypto v0.0.0-20220722155217-630584e8d5aa
+	golang.org/x/net v0.0.0-20211112202133-69e39bad7dc2
+)
@@ -0,0 +1,121 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Parse modes of a HandlerConfig.
+const (
+	// ParseModeRMeta emits the metadata of each embedded document separately,
+	// like MetaRecursive.
+	ParseModeRMeta = "rmeta"
+	// ParseModeConcatenate emits a single document with the content of all
+	// embedded documents.
+	ParseModeConcatenate = "concatenate"
+)
+
+// HandlerConfig configures how the server parses a FetchEmitTuple. Zero
+// fields use the server's defaults.
+type HandlerConfig struct {
+	// Type is the format of the extracted content.
+	Type ContentFormat `json:"type,omitempty"`
+	// ParseMode is ParseModeRMeta or ParseModeConcatenate.
+	ParseMode string `json:"parseMode,omitempty"`
+	// WriteLimit is the maximum number of characters to extract, or -1 for
+	// no limit. See WithWriteLimit.
+	WriteLimit int `json:"writeLimit,omitempty"`
+	// MaxEmbeddedResources is the maximum number of embedded documents to
+	// parse, or -1 for no limit. See WithMaxEmbeddedResources.
+	MaxEmbeddedResources int `json:"maxEmbeddedResources,omitempty"`
+}
+
+// A FetchEmitTuple tells a Tika 2.x server to fetch a document with a named
+// fetcher, parse it and send the result to a named emitter. Fetchers and
+// emitters are defined in the server configuration.
+type FetchEmitTuple struct {
+	// ID identifies the tuple in the server logs. FetchKey is used if it is
+	// empty.
+	ID       string `json:"id,omitempty"`
+	Fetcher  string `json:"fetcher"`
+	FetchKey string `json:"fetchKey"`
+	Emitter  string `json:"emitter"`
+	EmitKey  string `json:"emitKey"`
+	// Metadata is added to the metadata of the parsed document.
+	Metadata Metadata `json:"metadata,omitempty"`
+	// HandlerConfig configures the parse, or is nil for the defaults.
+	HandlerConfig *HandlerConfig `json:"handlerConfig,omitempty"`
+	// OnParseException is "emit" to emit documents that fail to parse with
+	// the exception in their metadata, or "skip" to skip them.
+	OnParseException string `json:"onParseException,omitempty"`
+}
+
+// FetchEmit returns a FetchEmitTuple that fetches key with fetcher and emits
+// it with emitter under the same key.
+func FetchEmit(fetcher, emitter, key string) FetchEmitTuple {
+	return FetchEmitTuple{ID: key, Fetcher: fetcher, FetchKey: key, Emitter: emitter, EmitKey: key}
+}
+
+// AsyncResult is the server's response to AsyncParse.
+type AsyncResult struct {
+	// Status is "ok" if the tuples were queued.
+	Status string `json:"status"`
+	// Added is the number of tuples that were queued.
+	Added int `json:"added"`
+	// Message explains why the tuples were not queued, if they weren't.
+	Message string `json:"msg"`
+}
+
+// AsyncParse queues tuples for parsing by a Tika 2.x server, which processes
+// them in the background. The server must be configured with the fetchers
+// and emitters the tuples use. AsyncParse returns an error if the server
+// didn't queue the tuples, for example because its queue is full.
+func (c *Client) AsyncParse(ctx context.Context, tuples []FetchEmitTuple, opts ...RequestOption) (*AsyncResult, error) {
+	req := make([]FetchEmitTuple, len(tuples))
+	copy(req, tuples)
+	for i := range req {
+		if req[i].ID == "" {
+			req[i].ID = req[i].FetchKey
+		}
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.call(ctx, bytes.NewReader(b), "POST", "/async", asyncHeader, opts)
+	if err != nil {
+		return nil, err
+	}
+	r := new(AsyncResult)
+	if err := json.Unmarshal(body, r); err != nil {
+		return nil, err
+	}
+	if r.Status != "ok" {
+		return r, fmt.Errorf("tika: async request not queued: %s: %s", r.Status, r.Message)
+	}
+	return r, nil
+}
+
+var asyncHeader = http.Header{
+	"Accept":       []string{"application/json"},
+	"Content-Type": []string{"application/json"},
+}
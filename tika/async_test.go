@@ -0,0 +1,88 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAsyncParse(t *testing.T) {
+	var method, path string
+	var got []map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		b, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Errorf("server got invalid JSON %q: %v", b, err)
+		}
+		fmt.Fprint(w, `{"status":"ok","added":2}`)
+	}))
+	defer ts.Close()
+	tuples := []FetchEmitTuple{
+		FetchEmit("fsf", "fse", "a.pdf"),
+		{
+			Fetcher: "fsf", FetchKey: "b.pdf", Emitter: "fse", EmitKey: "out/b.json",
+			HandlerConfig: &HandlerConfig{Type: ContentText, ParseMode: ParseModeConcatenate},
+		},
+	}
+	r, err := NewClient(nil, ts.URL).AsyncParse(context.Background(), tuples)
+	if err != nil {
+		t.Fatalf("AsyncParse got error: %v", err)
+	}
+	if r.Added != 2 {
+		t.Errorf("AsyncParse got %d added, want 2", r.Added)
+	}
+	if method != "POST" || path != "/async" {
+		t.Errorf("AsyncParse sent %s %s, want POST /async", method, path)
+	}
+	if len(got) != 2 {
+		t.Fatalf("AsyncParse sent %d tuples, want 2", len(got))
+	}
+	want := map[string]interface{}{"id": "a.pdf", "fetcher": "fsf", "fetchKey": "a.pdf", "emitter": "fse", "emitKey": "a.pdf"}
+	if fmt.Sprint(got[0]) != fmt.Sprint(want) {
+		t.Errorf("AsyncParse sent %v, want %v", got[0], want)
+	}
+	if got[1]["id"] != "b.pdf" {
+		t.Errorf("AsyncParse sent id %v, want the fetch key %q", got[1]["id"], "b.pdf")
+	}
+	if hc := fmt.Sprint(got[1]["handlerConfig"]); hc != "map[parseMode:concatenate type:text]" {
+		t.Errorf("AsyncParse sent handlerConfig %v, want map[parseMode:concatenate type:text]", hc)
+	}
+}
+
+func TestAsyncParseNotQueued(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, `{"status":"throttled","msg":"queue full"}`)
+	}))
+	defer ts.Close()
+	r, err := NewClient(nil, ts.URL).AsyncParse(context.Background(), []FetchEmitTuple{FetchEmit("f", "e", "k")})
+	if err == nil {
+		t.Fatalf("AsyncParse got no error, want an error")
+	}
+	if r == nil || r.Message != "queue full" {
+		t.Errorf("AsyncParse got result %+v, want the server message", r)
+	}
+	if _, err := errorClient.AsyncParse(context.Background(), nil); err == nil {
+		t.Errorf("AsyncParse got no error, want an error")
+	}
+}
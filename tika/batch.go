@@ -0,0 +1,229 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// A BatchInput is a document to parse with a Batch.
+type BatchInput struct {
+	// ID identifies the document in its BatchResult. If ID is empty, Path is
+	// used.
+	ID string
+	// Path is the file to parse, if Reader is nil.
+	Path string
+	// Reader is the content of the document. It is read once and, if it is
+	// an io.Closer, closed after the document is parsed.
+	Reader io.Reader
+
+	// open opens the document, if it is not nil and Reader is nil.
+	open func() (io.Reader, error)
+}
+
+// A BatchResult is the result of parsing one BatchInput.
+type BatchResult struct {
+	// ID is the ID of the input.
+	ID string
+	// Text is the content of the document. By default it includes the
+	// content of embedded documents. With WithBatchMetadata, it only has the
+	// content of the document itself.
+	Text string
+	// Metadata is the metadata of the document, and Embedded that of every
+	// embedded document. They are only set with WithBatchMetadata.
+	Metadata Metadata
+	Embedded []Metadata
+	// Digest is the hex-encoded SHA-256 digest of the document. It is only
+	// set with WithDeduplication or WithSeenStore.
+	Digest string
+	// ServerDigests are the digests computed by the server, keyed by
+	// algorithm. They are only set with WithBatchMetadata, for servers
+	// started with WithDigest.
+	ServerDigests map[string]string
+	// Duplicate is set if the document was skipped because a document with
+	// the same digest was already processed. Text and Metadata are not set.
+	Duplicate bool
+	// Err is the error parsing the document, if any.
+	Err error
+}
+
+// A Batch parses many documents concurrently, with bounded parallelism,
+// against one or more servers. Create a Batch with NewBatch and parse
+// documents with Run or RunFiles.
+type Batch struct {
+	clients  []*Client
+	workers  int
+	opts     []RequestOption
+	metadata bool
+	dedup    bool
+	seen     SeenStore
+}
+
+// A BatchOption configures a Batch. See NewBatch.
+type BatchOption func(*Batch)
+
+// WithWorkers sets the number of documents a Batch parses at the same time.
+// The default is one per Client.
+func WithWorkers(n int) BatchOption {
+	return func(b *Batch) {
+		b.workers = n
+	}
+}
+
+// WithBatchRequestOptions sets the options every document of a Batch is
+// parsed with.
+func WithBatchRequestOptions(opts ...RequestOption) BatchOption {
+	return func(b *Batch) {
+		b.opts = append(b.opts, opts...)
+	}
+}
+
+// WithBatchMetadata makes a Batch return the metadata of each document, and
+// every document embedded in it, along with the text. The documents are
+// parsed with RecursiveMetadata instead of Parse.
+func WithBatchMetadata() BatchOption {
+	return func(b *Batch) {
+		b.metadata = true
+	}
+}
+
+// NewBatch creates a new Batch that sends requests to clients. Workers use
+// the clients in turn, so documents are spread evenly over the servers. To
+// use every server of a ServerPool, pass its Client.
+func NewBatch(clients []*Client, opts ...BatchOption) (*Batch, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("no clients specified")
+	}
+	b := &Batch{clients: clients}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.workers <= 0 {
+		b.workers = len(clients)
+	}
+	return b, nil
+}
+
+// Run parses every input received from inputs and sends a BatchResult for
+// each to the returned channel, in the order they finish. The channel is
+// closed once inputs is closed and every document is parsed, or once ctx is
+// done. The caller must receive every result, or cancel ctx, to avoid leaking
+// the workers.
+func (b *Batch) Run(ctx context.Context, inputs <-chan BatchInput) <-chan BatchResult {
+	results := make(chan BatchResult)
+	d := b.newDeduplicator()
+	var wg sync.WaitGroup
+	for i := 0; i < b.workers; i++ {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			for {
+				var in BatchInput
+				var ok bool
+				select {
+				case in, ok = <-inputs:
+				case <-ctx.Done():
+					return
+				}
+				if !ok {
+					return
+				}
+				select {
+				case results <- b.parse(ctx, c, d, in):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(b.clients[i%len(b.clients)])
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// RunFiles is like Run for the files at paths.
+func (b *Batch) RunFiles(ctx context.Context, paths []string) <-chan BatchResult {
+	inputs := make(chan BatchInput)
+	go func() {
+		defer close(inputs)
+		for _, p := range paths {
+			select {
+			case inputs <- BatchInput{Path: p}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return b.Run(ctx, inputs)
+}
+
+// parse parses in with c. If d is not nil, it skips duplicate documents.
+func (b *Batch) parse(ctx context.Context, c *Client, d *deduplicator, in BatchInput) BatchResult {
+	res := BatchResult{ID: in.ID}
+	if res.ID == "" {
+		res.ID = in.Path
+	}
+	r := in.Reader
+	if r == nil {
+		open := in.open
+		if open == nil {
+			open = func() (io.Reader, error) { return os.Open(in.Path) }
+		}
+		var err error
+		if r, err = open(); err != nil {
+			res.Err = err
+			return res
+		}
+	}
+	if rc, ok := r.(io.Closer); ok {
+		defer rc.Close()
+	}
+	if d != nil {
+		return d.parse(res, r, func(r io.Reader, res BatchResult) BatchResult {
+			return b.send(ctx, c, r, res)
+		})
+	}
+	return b.send(ctx, c, r, res)
+}
+
+// send parses r with c and fills in res.
+func (b *Batch) send(ctx context.Context, c *Client, r io.Reader, res BatchResult) BatchResult {
+	if !b.metadata {
+		res.Text, res.Err = c.Parse(ctx, r, b.opts...)
+		return res
+	}
+	docs, err := c.RecursiveMetadata(ctx, r, ContentText, b.opts...)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if len(docs) == 0 {
+		res.Err = fmt.Errorf("no documents returned")
+		return res
+	}
+	res.Text = docs[0].Content()
+	res.Metadata = docs[0]
+	res.Embedded = docs[1:]
+	res.ServerDigests = docs[0].Digests()
+	return res
+}
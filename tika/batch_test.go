@@ -0,0 +1,174 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatchRun(t *testing.T) {
+	var mu sync.Mutex
+	var active, maxActive int32
+	servers := make(map[string]int)
+	newServer := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			mu.Lock()
+			if n > maxActive {
+				maxActive = n
+			}
+			servers[name]++
+			mu.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) == "bad" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				return
+			}
+			fmt.Fprint(w, strings.ToUpper(string(body)))
+		}))
+	}
+	ts1, ts2 := newServer("1"), newServer("2")
+	defer ts1.Close()
+	defer ts2.Close()
+	b, err := NewBatch([]*Client{NewClient(nil, ts1.URL), NewClient(nil, ts2.URL)}, WithWorkers(3))
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+
+	inputs := make(chan BatchInput)
+	go func() {
+		defer close(inputs)
+		for i := 0; i < 10; i++ {
+			inputs <- BatchInput{ID: fmt.Sprint(i), Reader: strings.NewReader(fmt.Sprintf("doc%d", i))}
+		}
+		inputs <- BatchInput{ID: "bad", Reader: strings.NewReader("bad")}
+	}()
+	got := make(map[string]BatchResult)
+	for res := range b.Run(context.Background(), inputs) {
+		got[res.ID] = res
+	}
+	if len(got) != 11 {
+		t.Fatalf("got %d results, want 11", len(got))
+	}
+	for i := 0; i < 10; i++ {
+		res := got[fmt.Sprint(i)]
+		if want := fmt.Sprintf("DOC%d", i); res.Err != nil || res.Text != want {
+			t.Errorf("result %d = %q, %v, want %q", i, res.Text, res.Err, want)
+		}
+	}
+	if got["bad"].Err == nil {
+		t.Errorf("result bad got no error")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if maxActive > 3 {
+		t.Errorf("%d documents parsed at once, want at most 3", maxActive)
+	}
+	if servers["1"] == 0 || servers["2"] == 0 {
+		t.Errorf("requests per server = %v, want both servers used", servers)
+	}
+}
+
+func TestBatchRunFiles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rmeta/text" {
+			t.Errorf("got path %q, want /rmeta/text", r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, `[{"X-TIKA:content":%q,"Content-Type":"text/plain","X-TIKA:digest:SHA256":"abc"},{"X-TIKA:content":"embedded"}]`, body)
+	}))
+	defer ts.Close()
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"a.txt", "b.txt"} {
+		p := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(p, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	paths = append(paths, filepath.Join(dir, "missing.txt"))
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)}, WithBatchMetadata())
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+
+	var results []BatchResult
+	for res := range b.RunFiles(context.Background(), paths) {
+		results = append(results, res)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	for i, name := range []string{"a.txt", "b.txt"} {
+		res := results[i]
+		if res.Err != nil || res.Text != name || res.Metadata.ContentType() != "text/plain" || len(res.Embedded) != 1 {
+			t.Errorf("result %s = %+v, want text, metadata and one embedded document", name, res)
+		}
+		if want := map[string]string{"SHA256": "abc"}; !reflect.DeepEqual(res.ServerDigests, want) {
+			t.Errorf("result %s got server digests %v, want %v", name, res.ServerDigests, want)
+		}
+	}
+	if !os.IsNotExist(results[2].Err) {
+		t.Errorf("result missing.txt got error %v, want not exist", results[2].Err)
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "text")
+	}))
+	defer ts.Close()
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)})
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	inputs := make(chan BatchInput)
+	results := b.Run(ctx, inputs)
+	cancel()
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Errorf("got a result after cancelling, want none")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("results not closed after cancelling")
+	}
+}
+
+func TestNewBatchNoClients(t *testing.T) {
+	if _, err := NewBatch(nil); err == nil {
+		t.Errorf("NewBatch(nil) got no error")
+	}
+}
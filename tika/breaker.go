@@ -0,0 +1,136 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Client methods without contacting the server
+// while the circuit breaker is open. See WithCircuitBreaker.
+var ErrCircuitOpen = errors.New("tika: circuit breaker open")
+
+// A breaker is a circuit breaker. It opens after threshold consecutive
+// failures, failing every request until cooldown has passed. Then it lets a
+// single probe request through: if the probe succeeds, the breaker closes,
+// otherwise it opens again.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	// onChange, if not nil, is called when the breaker opens or closes.
+	onChange func(open bool)
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // openedAt is zero if the breaker is closed.
+	probing  bool
+}
+
+// allow reports whether a request may be sent.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record records the result of a request allowed by allow. ctx is the
+// context of the request.
+func (b *breaker) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	wasOpen := !b.openedAt.IsZero()
+	b.probing = false
+	if isClientFailure(ctx, err) {
+		// Says nothing about the server, so leave the breaker as it is.
+		b.mu.Unlock()
+		return
+	}
+	if !isServerFailure(err) {
+		b.failures = 0
+		b.openedAt = time.Time{}
+	} else {
+		b.failures++
+		if b.failures >= b.threshold || wasOpen {
+			b.openedAt = time.Now()
+		}
+	}
+	open := !b.openedAt.IsZero()
+	b.mu.Unlock()
+	if open != wasOpen && b.onChange != nil {
+		b.onChange(open)
+	}
+}
+
+// isOpen reports whether the breaker is failing requests.
+func (b *breaker) isOpen() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.openedAt.IsZero()
+}
+
+// isServerFailure reports whether err means the server is unhealthy, as
+// opposed to the server rejecting a particular document: a 5xx response, or
+// a network error such as a refused connection or a timeout.
+func isServerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if e, ok := err.(*Error); ok {
+		return e.StatusCode >= 500
+	}
+	var ne net.Error
+	return errors.As(err, &ne)
+}
+
+// isClientFailure reports whether err was caused by the caller rather than
+// the server: ctx was cancelled or its deadline passed, or the request was
+// rejected before it was sent, like an *UploadTooLargeError.
+func isClientFailure(ctx context.Context, err error) bool {
+	if err == nil {
+		return false
+	}
+	if ctx.Err() != nil {
+		return true
+	}
+	var tooLarge *UploadTooLargeError
+	return errors.As(err, &tooLarge)
+}
+
+// wrap returns a send function that sends requests with send while b allows
+// it. ctx is the context of the requests.
+func (b *breaker) wrap(ctx context.Context, send func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		if !b.allow() {
+			return nil, ErrCircuitOpen
+		}
+		resp, err := send(req)
+		b.record(ctx, err)
+		return resp, err
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	status := http.StatusInternalServerError
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(status)
+		fmt.Fprint(w, "1.21")
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithCircuitBreaker(2, 50*time.Millisecond))
+	ctx := context.Background()
+
+	// A client error doesn't count as a failure.
+	status = http.StatusUnsupportedMediaType
+	c.Version(ctx)
+	status = http.StatusInternalServerError
+	c.Version(ctx)
+	if c.breaker.isOpen() {
+		t.Fatalf("breaker opened after one server failure, want it to stay closed")
+	}
+	c.Version(ctx)
+	if !c.breaker.isOpen() {
+		t.Fatalf("breaker still closed after two consecutive failures, want it open")
+	}
+	if _, err := c.Version(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Version with an open breaker got %v, want %v", err, ErrCircuitOpen)
+	}
+	if requests != 3 {
+		t.Errorf("server got %d requests, want 3", requests)
+	}
+
+	// A failed probe reopens the breaker immediately.
+	time.Sleep(60 * time.Millisecond)
+	if _, err := c.Version(ctx); errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Version after the cooldown got %v, want a probe", err)
+	}
+	if _, err := c.Version(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Version after a failed probe got %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// A successful probe closes the breaker.
+	time.Sleep(60 * time.Millisecond)
+	status = http.StatusOK
+	for i := 0; i < 2; i++ {
+		if _, err := c.Version(ctx); err != nil {
+			t.Errorf("Version after a successful probe got %v, want no error", err)
+		}
+	}
+	if c.breaker.isOpen() {
+		t.Errorf("breaker open after a successful probe, want it closed")
+	}
+}
+
+func TestCircuitBreakerClientFailures(t *testing.T) {
+	block := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			io.Copy(ioutil.Discard, r.Body)
+			return
+		}
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(block)
+	c := NewClientWithOptions(ts.URL, WithCircuitBreaker(1, time.Minute), WithMaxUploadSize(1))
+
+	// Cancelled requests and documents rejected by the client don't count.
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := c.Version(ctx)
+		cancel()
+		if err == nil {
+			t.Fatalf("Version with a cancelled context got no error")
+		}
+		if _, err := c.Parse(context.Background(), struct{ io.Reader }{strings.NewReader("too large")}); err == nil {
+			t.Fatalf("Parse of a document that is too large got no error")
+		}
+	}
+	if c.breaker.isOpen() {
+		t.Fatalf("breaker opened after client failures, want it closed")
+	}
+
+	// A server that can't be reached does.
+	ts.Close()
+	if _, err := c.Version(context.Background()); err == nil {
+		t.Fatalf("Version of a stopped server got no error")
+	}
+	if !c.breaker.isOpen() {
+		t.Errorf("breaker closed after a connection error, want it open")
+	}
+}
@@ -0,0 +1,277 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A Cache stores the responses of a Client. Keys are derived from a digest
+// of the document, the request and the server version, so a cached response
+// is only returned for identical bytes parsed the same way by the same
+// version of Tika. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key, if there is one.
+	Get(key string) ([]byte, bool)
+	// Set stores value for key.
+	Set(key string, value []byte)
+}
+
+// WithCache caches the responses to requests that parse or detect a
+// document, such as Parse, MetaJSON, Detect and Unpack, in c. Requests with
+// side effects, like AsyncParse, are never cached. Re-submitting identical
+// bytes with the same options returns the cached response without sending a
+// request. Responses are buffered in memory to be cached, so WithCache is
+// unsuitable for very large outputs. Use NewMemoryCache for an in-memory
+// cache.
+func WithCache(c Cache) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.cache = c
+	}
+}
+
+// responseCache caches the responses of a Client in c.
+type responseCache struct {
+	c Cache
+
+	mu sync.Mutex
+	// version is the server version, once known. It is forgotten when the
+	// server fails, since it may come back as a different version.
+	version string
+}
+
+// cached is like sendRequest, but returns the response from c.cache if it has
+// one for the request.
+func (c *Client) cached(ctx context.Context, input io.Reader, method, path string, header http.Header, opts []RequestOption, stats *callStats) (*http.Response, error) {
+	cfg := newRequestConfig(c.defaults, opts)
+	if input == nil || cfg.noBody || !cacheable(method, path) {
+		return c.sendRequest(ctx, input, method, path, header, opts, stats)
+	}
+	if c.maxUpload > 0 {
+		// Limit the input before buffering it, so a document that is too
+		// large isn't read into memory.
+		var err error
+		if input, err = c.limitUpload(input); err != nil {
+			return nil, err
+		}
+	}
+	body, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+	version, err := c.cacheVersion(ctx)
+	if err != nil {
+		// Without the version, a response may be stale, so don't cache it.
+		return c.sendRequest(ctx, bytes.NewReader(body), method, path, header, opts, stats)
+	}
+	key := cacheKey(version, method, path, body, c.header, header, cfg.header)
+	if v, ok := c.cache.c.Get(key); ok {
+		if h, b, err := decodeCached(v); err == nil {
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Header:        h,
+				ContentLength: int64(len(b)),
+				Body:          ioutil.NopCloser(bytes.NewReader(b)),
+			}, nil
+		}
+		// Not written by this version of the package, replace it.
+	}
+	resp, err := c.sendRequest(ctx, bytes.NewReader(body), method, path, header, opts, stats)
+	if err != nil {
+		if isServerFailure(err) {
+			c.cache.mu.Lock()
+			c.cache.version = ""
+			c.cache.mu.Unlock()
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.c.Set(key, encodeCached(resp.Header, b))
+	resp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return resp, nil
+}
+
+// cacheablePaths are the endpoints whose responses only depend on the
+// document and the request, so they can be cached.
+var cacheablePaths = []string{"/tika", "/meta", "/rmeta", "/detect/stream", "/language/stream", "/unpack"}
+
+// cacheable reports whether the response to method and path can be cached.
+func cacheable(method, path string) bool {
+	if method != "PUT" {
+		return false
+	}
+	for _, p := range cacheablePaths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCached returns the cache value of a response with header h and body
+// b: the header in wire format, a blank line and the body.
+func encodeCached(h http.Header, b []byte) []byte {
+	var buf bytes.Buffer
+	h.Write(&buf)
+	buf.WriteString("\r\n")
+	buf.Write(b)
+	return buf.Bytes()
+}
+
+// decodeCached returns the header and body of a cache value written by
+// encodeCached.
+func decodeCached(v []byte) (http.Header, []byte, error) {
+	br := bufio.NewReader(bytes.NewReader(v))
+	h, err := textproto.NewReader(br).ReadMIMEHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, nil, err
+	}
+	return http.Header(h), b, nil
+}
+
+// cacheVersion returns the version of the server, requesting it the first
+// time and after the server failed.
+func (c *Client) cacheVersion(ctx context.Context) (string, error) {
+	c.cache.mu.Lock()
+	v := c.cache.version
+	c.cache.mu.Unlock()
+	if v != "" {
+		return v, nil
+	}
+	resp, err := c.sendRequest(ctx, nil, "GET", "/version", nil, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	v = strings.TrimSpace(string(b))
+	c.cache.mu.Lock()
+	c.cache.version = v
+	c.cache.mu.Unlock()
+	return v, nil
+}
+
+// cacheKey returns the key of a request with the given body and headers to
+// a server of the given version.
+func cacheKey(version, method, path string, body []byte, headers ...http.Header) string {
+	merged := make(http.Header)
+	for _, h := range headers {
+		for k, v := range h {
+			merged[k] = v
+		}
+	}
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	io.WriteString(h, version+"\n"+method+" "+path+"\n")
+	for _, k := range keys {
+		io.WriteString(h, k+": "+strings.Join(merged[k], ", ")+"\n")
+	}
+	io.WriteString(h, "\n")
+	bodySum := sha256.Sum256(body)
+	h.Write(bodySum[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryCache is a Cache that keeps up to a maximum number of responses in
+// memory, evicting the least recently used. Create one with NewMemoryCache.
+type MemoryCache struct {
+	max int
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type memoryCacheEntry struct {
+	key   string
+	value []byte
+}
+
+// NewMemoryCache creates a new MemoryCache that holds up to max responses. If
+// max is 0, the number of responses is unlimited.
+func NewMemoryCache(max int) *MemoryCache {
+	return &MemoryCache{
+		max:     max,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	m.ll.MoveToFront(e)
+	return e.Value.(*memoryCacheEntry).value, true
+}
+
+// Set implements Cache.
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.entries[key]; ok {
+		m.ll.MoveToFront(e)
+		e.Value.(*memoryCacheEntry).value = value
+		return
+	}
+	m.entries[key] = m.ll.PushFront(&memoryCacheEntry{key: key, value: value})
+	for m.max > 0 && m.ll.Len() > m.max {
+		oldest := m.ll.Back()
+		m.ll.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheEntry).key)
+	}
+}
+
+// Len returns the number of responses in m.
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
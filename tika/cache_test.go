@@ -0,0 +1,236 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	version := "Apache Tika 2.9.1"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		v := version
+		mu.Unlock()
+		if r.URL.Path == "/version" {
+			fmt.Fprint(w, v)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s %s", r.URL.Path, body, r.Header.Get("X-Tika-OCRLanguage"))
+	}))
+	defer ts.Close()
+	cache := NewMemoryCache(0)
+	c := NewClientWithOptions(ts.URL, WithCache(cache))
+	ctx := context.Background()
+	parse := func(input string, opts ...RequestOption) string {
+		t.Helper()
+		got, err := c.Parse(ctx, strings.NewReader(input), opts...)
+		if err != nil {
+			t.Fatalf("Parse(%q) got error: %v", input, err)
+		}
+		return got
+	}
+	count := func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[path]
+	}
+
+	if got, want := parse("doc"), "/tika doc "; got != want {
+		t.Errorf("Parse = %q, want %q", got, want)
+	}
+	if got, want := parse("doc"), "/tika doc "; got != want {
+		t.Errorf("cached Parse = %q, want %q", got, want)
+	}
+	if n := count("/tika"); n != 1 {
+		t.Errorf("sent %d parse requests, want 1", n)
+	}
+	if n := count("/version"); n != 1 {
+		t.Errorf("sent %d version requests, want 1", n)
+	}
+
+	// Different bytes, options or endpoints are not served from the cache.
+	parse("other")
+	if got, want := parse("doc", WithOCRLanguage("fra")), "/tika doc fra"; got != want {
+		t.Errorf("Parse(fra) = %q, want %q", got, want)
+	}
+	if _, err := c.Detect(ctx, strings.NewReader("doc")); err != nil {
+		t.Fatalf("Detect got error: %v", err)
+	}
+	if n := count("/tika"); n != 3 {
+		t.Errorf("sent %d parse requests, want 3", n)
+	}
+	if cache.Len() != 4 {
+		t.Errorf("cache has %d entries, want 4", cache.Len())
+	}
+
+	// A client talking to a different server version doesn't share entries.
+	mu.Lock()
+	version = "Apache Tika 3.0.0"
+	mu.Unlock()
+	c = NewClientWithOptions(ts.URL, WithCache(cache))
+	parse("doc")
+	if n := count("/tika"); n != 4 {
+		t.Errorf("sent %d parse requests after upgrading, want 4", n)
+	}
+}
+
+func TestWithCacheEndpoints(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/version" {
+			fmt.Fprint(w, "Apache Tika 2.9.1")
+			return
+		}
+		w.Header().Set("X-Test", "yes")
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithCache(NewMemoryCache(0)))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		for _, req := range []string{"PUT /tika", "PUT /rmeta/text", "POST /async", "PUT /translate/all/x/en/fr"} {
+			f := strings.Fields(req)
+			resp, err := c.do(ctx, strings.NewReader("doc"), f[0], f[1], nil, nil)
+			if err != nil {
+				t.Fatalf("%s got error: %v", req, err)
+			}
+			b, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(b) != "ok" || resp.Header.Get("X-Test") != "yes" {
+				t.Errorf("%s (try %d) = %q with X-Test %q, want %q with the response headers", req, i, b, resp.Header.Get("X-Test"), "ok")
+			}
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string]int{"GET /version": 1, "PUT /tika": 1, "PUT /rmeta/text": 1, "POST /async": 2, "PUT /translate/all/x/en/fr": 2}
+	for req, n := range want {
+		if requests[req] != n {
+			t.Errorf("sent %d %s requests, want %d", requests[req], req, n)
+		}
+	}
+}
+
+func TestWithCacheErrors(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			fmt.Fprint(w, "Apache Tika 2.9.1")
+			return
+		}
+		requests++
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer ts.Close()
+	cache := NewMemoryCache(0)
+	c := NewClientWithOptions(ts.URL, WithCache(cache))
+	for i := 0; i < 2; i++ {
+		if _, err := c.Parse(context.Background(), strings.NewReader("doc")); err == nil {
+			t.Errorf("Parse got no error")
+		}
+	}
+	if requests != 2 || cache.Len() != 0 {
+		t.Errorf("sent %d requests and cached %d, want errors not to be cached", requests, cache.Len())
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	m := NewMemoryCache(2)
+	m.Set("a", []byte("1"))
+	m.Set("b", []byte("2"))
+	m.Get("a")
+	m.Set("c", []byte("3"))
+	if _, ok := m.Get("b"); ok {
+		t.Errorf("Get(b) found the least recently used entry, want it evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := m.Get(key); !ok {
+			t.Errorf("Get(%s) not found", key)
+		}
+	}
+	m.Set("a", []byte("4"))
+	if v, _ := m.Get("a"); string(v) != "4" || m.Len() != 2 {
+		t.Errorf("Get(a) = %q with %d entries, want \"4\" with 2", v, m.Len())
+	}
+}
+
+func TestWithCacheVersionChange(t *testing.T) {
+	var mu sync.Mutex
+	version, fail := "Apache Tika 2.9.1", false
+	versions, parses := 0, 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/version" {
+			versions++
+			fmt.Fprint(w, version)
+			return
+		}
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		parses++
+		fmt.Fprint(w, version)
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithCache(NewMemoryCache(0)))
+	ctx := context.Background()
+	set := func(v string, f bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		version, fail = v, f
+	}
+
+	if _, err := c.Parse(ctx, strings.NewReader("doc")); err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	// The server goes down and comes back upgraded.
+	set("Apache Tika 3.0.0", true)
+	if _, err := c.Parse(ctx, strings.NewReader("other doc")); err == nil {
+		t.Fatalf("Parse of an unavailable server got no error")
+	}
+	set("Apache Tika 3.0.0", false)
+	got, err := c.Parse(ctx, strings.NewReader("doc"))
+	if err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if want := "Apache Tika 3.0.0"; got != want {
+		t.Errorf("Parse after an upgrade = %q, want %q from the new server", got, want)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if versions != 2 || parses != 2 {
+		t.Errorf("sent %d version and %d parse requests, want 2 and 2", versions, parses)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+// Walk calls fn for p and all of its descendants, parents before children,
+// stopping early if fn returns false.
+func (p *Parser) Walk(fn func(p *Parser) bool) bool {
+	if !fn(p) {
+		return false
+	}
+	for i := range p.Children {
+		if !p.Children[i].Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the parser with the given Java class name in the tree rooted
+// at p, or nil if there is none.
+func (p *Parser) Find(name string) *Parser {
+	var found *Parser
+	p.Walk(func(p *Parser) bool {
+		if p.Name == name {
+			found = p
+		}
+		return found == nil
+	})
+	return found
+}
+
+// Leaves returns the parsers in the tree rooted at p that aren't composite,
+// which are the parsers that do the work.
+func (p *Parser) Leaves() []Parser {
+	var r []Parser
+	p.Walk(func(p *Parser) bool {
+		if !p.Composite {
+			r = append(r, *p)
+		}
+		return true
+	})
+	return r
+}
+
+// ForType returns the leaf parsers in the tree rooted at p that support
+// mimeType, such as "application/pdf".
+func (p *Parser) ForType(mimeType string) []Parser {
+	var r []Parser
+	for _, leaf := range p.Leaves() {
+		for _, t := range leaf.SupportedTypes {
+			if t == mimeType {
+				r = append(r, leaf)
+				break
+			}
+		}
+	}
+	return r
+}
+
+// Walk calls fn for d and all of its descendants, parents before children,
+// stopping early if fn returns false.
+func (d *Detector) Walk(fn func(d *Detector) bool) bool {
+	if !fn(d) {
+		return false
+	}
+	for i := range d.Children {
+		if !d.Children[i].Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the detector with the given Java class name in the tree rooted
+// at d, or nil if there is none.
+func (d *Detector) Find(name string) *Detector {
+	var found *Detector
+	d.Walk(func(d *Detector) bool {
+		if d.Name == name {
+			found = d
+		}
+		return found == nil
+	})
+	return found
+}
+
+// Names returns the names of the detectors in the tree rooted at d that
+// aren't composite.
+func (d *Detector) Names() []string {
+	var r []string
+	d.Walk(func(d *Detector) bool {
+		if !d.Composite {
+			r = append(r, d.Name)
+		}
+		return true
+	})
+	return r
+}
+
+// A MIMETypeMap maps MIME Type names to their MIMEType, as returned by
+// MIMETypes, for example:
+//
+//	mt, err := c.MIMETypes(ctx)
+//	...
+//	if tika.MIMETypeMap(mt).IsSubtypeOf("application/xhtml+xml", "application/xml") {
+//		...
+//	}
+type MIMETypeMap map[string]MIMEType
+
+// Canonical returns the name of the MIME Type that has name as a name or
+// alias, or "" if there is none.
+func (m MIMETypeMap) Canonical(name string) string {
+	if _, ok := m[name]; ok {
+		return name
+	}
+	for canonical, t := range m {
+		for _, a := range t.Alias {
+			if a == name {
+				return canonical
+			}
+		}
+	}
+	return ""
+}
+
+// Aliases returns the aliases of the MIME Type name.
+func (m MIMETypeMap) Aliases(name string) []string {
+	return m[m.Canonical(name)].Alias
+}
+
+// SuperTypes returns the chain of supertypes of the MIME Type name, closest
+// first.
+func (m MIMETypeMap) SuperTypes(name string) []string {
+	var r []string
+	seen := map[string]bool{}
+	for t := m.Canonical(name); t != "" && !seen[t]; {
+		seen[t] = true
+		super := m[t].SuperType
+		if super == "" {
+			break
+		}
+		r = append(r, super)
+		if c := m.Canonical(super); c != "" {
+			super = c
+		}
+		t = super
+	}
+	return r
+}
+
+// IsSubtypeOf reports whether the MIME Type name is super or one of its
+// subtypes, for example IsSubtypeOf("application/pdf",
+// "application/octet-stream").
+func (m MIMETypeMap) IsSubtypeOf(name, super string) bool {
+	if c := m.Canonical(super); c != "" {
+		super = c
+	}
+	if c := m.Canonical(name); c == super || name == super {
+		return true
+	}
+	for _, t := range m.SuperTypes(name) {
+		if t == super || m.Canonical(t) == super {
+			return true
+		}
+	}
+	return false
+}
+
+// ParserFor returns the Java class name of the parser assigned to the MIME
+// Type name, or "" if there is none.
+func (m MIMETypeMap) ParserFor(name string) string {
+	return m[m.Canonical(name)].Parser
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testParsers = &Parser{
+	Name:      "org.apache.tika.parser.DefaultParser",
+	Composite: true,
+	Children: []Parser{
+		{Name: "org.apache.tika.parser.pdf.PDFParser", SupportedTypes: []string{"application/pdf"}},
+		{
+			Name:      "org.apache.tika.parser.CompositeParser",
+			Composite: true,
+			Children: []Parser{
+				{Name: "org.apache.tika.parser.txt.TXTParser", SupportedTypes: []string{"text/plain"}},
+				{Name: "org.apache.tika.parser.ocr.TesseractOCRParser", SupportedTypes: []string{"image/png", "application/pdf"}},
+			},
+		},
+	},
+}
+
+func TestParserFind(t *testing.T) {
+	for _, name := range []string{"org.apache.tika.parser.DefaultParser", "org.apache.tika.parser.txt.TXTParser"} {
+		if got := testParsers.Find(name); got == nil || got.Name != name {
+			t.Errorf("Find(%q) got %v, want the parser", name, got)
+		}
+	}
+	if got := testParsers.Find("missing"); got != nil {
+		t.Errorf("Find(%q) got %v, want nil", "missing", got)
+	}
+}
+
+func TestParserLeaves(t *testing.T) {
+	var got []string
+	for _, p := range testParsers.Leaves() {
+		got = append(got, p.Name)
+	}
+	want := []string{
+		"org.apache.tika.parser.pdf.PDFParser",
+		"org.apache.tika.parser.txt.TXTParser",
+		"org.apache.tika.parser.ocr.TesseractOCRParser",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaves got %q, want %q", got, want)
+	}
+}
+
+func TestParserForType(t *testing.T) {
+	tests := []struct {
+		mimeType string
+		want     []string
+	}{
+		{"application/pdf", []string{"org.apache.tika.parser.pdf.PDFParser", "org.apache.tika.parser.ocr.TesseractOCRParser"}},
+		{"text/plain", []string{"org.apache.tika.parser.txt.TXTParser"}},
+		{"application/unknown", nil},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, p := range testParsers.ForType(test.mimeType) {
+			got = append(got, p.Name)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ForType(%q) got %q, want %q", test.mimeType, got, test.want)
+		}
+	}
+}
+
+var testDetectors = &Detector{
+	Name:      "org.apache.tika.detect.DefaultDetector",
+	Composite: true,
+	Children: []Detector{
+		{Name: "org.apache.tika.parser.microsoft.POIFSContainerDetector"},
+		{
+			Name:      "org.apache.tika.detect.CompositeDetector",
+			Composite: true,
+			Children:  []Detector{{Name: "org.apache.tika.mime.MimeTypes"}},
+		},
+	},
+}
+
+func TestDetectorFind(t *testing.T) {
+	for _, name := range []string{"org.apache.tika.detect.DefaultDetector", "org.apache.tika.mime.MimeTypes"} {
+		if got := testDetectors.Find(name); got == nil || got.Name != name {
+			t.Errorf("Find(%q) got %v, want the detector", name, got)
+		}
+	}
+	if got := testDetectors.Find("missing"); got != nil {
+		t.Errorf("Find(%q) got %v, want nil", "missing", got)
+	}
+}
+
+func TestDetectorNames(t *testing.T) {
+	want := []string{"org.apache.tika.parser.microsoft.POIFSContainerDetector", "org.apache.tika.mime.MimeTypes"}
+	if got := testDetectors.Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Names got %q, want %q", got, want)
+	}
+}
+
+var testMIMETypes = MIMETypeMap{
+	"application/octet-stream": {},
+	"application/xml":          {Alias: []string{"text/xml"}, SuperType: "text/plain"},
+	"application/xhtml+xml":    {SuperType: "text/xml"},
+	"application/pdf":          {Alias: []string{"application/x-pdf"}, SuperType: "application/octet-stream", Parser: "org.apache.tika.parser.pdf.PDFParser"},
+	"text/plain":               {SuperType: "application/octet-stream"},
+	"loop/a":                   {SuperType: "loop/b"},
+	"loop/b":                   {SuperType: "loop/a"},
+}
+
+func TestMIMETypeMapLookups(t *testing.T) {
+	if got, want := testMIMETypes.Canonical("application/x-pdf"), "application/pdf"; got != want {
+		t.Errorf("Canonical(%q) got %q, want %q", "application/x-pdf", got, want)
+	}
+	if got := testMIMETypes.Canonical("missing/type"); got != "" {
+		t.Errorf("Canonical(%q) got %q, want \"\"", "missing/type", got)
+	}
+	if got, want := testMIMETypes.Aliases("application/pdf"), []string{"application/x-pdf"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Aliases(%q) got %q, want %q", "application/pdf", got, want)
+	}
+	if got, want := testMIMETypes.ParserFor("application/x-pdf"), "org.apache.tika.parser.pdf.PDFParser"; got != want {
+		t.Errorf("ParserFor(%q) got %q, want %q", "application/x-pdf", got, want)
+	}
+}
+
+func TestMIMETypeMapSuperTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"application/xhtml+xml", []string{"text/xml", "text/plain", "application/octet-stream"}},
+		{"application/octet-stream", nil},
+		{"missing/type", nil},
+		{"loop/a", []string{"loop/b", "loop/a"}},
+	}
+	for _, test := range tests {
+		if got := testMIMETypes.SuperTypes(test.name); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SuperTypes(%q) got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestMIMETypeMapIsSubtypeOf(t *testing.T) {
+	tests := []struct {
+		name, super string
+		want        bool
+	}{
+		{"application/pdf", "application/octet-stream", true},
+		{"application/xhtml+xml", "application/xml", true},
+		{"application/xhtml+xml", "text/xml", true},
+		{"application/pdf", "application/pdf", true},
+		{"application/x-pdf", "application/pdf", true},
+		{"application/pdf", "text/plain", false},
+		{"loop/a", "text/plain", false},
+	}
+	for _, test := range tests {
+		if got := testMIMETypes.IsSubtypeOf(test.name, test.super); got != test.want {
+			t.Errorf("IsSubtypeOf(%q, %q) got %v, want %v", test.name, test.super, got, test.want)
+		}
+	}
+}
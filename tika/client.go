@@ -0,0 +1,82 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io"
+)
+
+// TikaClient is the interface implemented by Client. Depend on it instead of
+// *Client to replace Tika with a fake in tests, or to wrap a Client with
+// decorators. A fake can embed TikaClient and only implement the methods it
+// needs; calling any other method panics.
+//
+// Methods may be added to TikaClient as they are added to Client, so
+// implementations outside this package should embed TikaClient.
+type TikaClient interface {
+	// Parsing.
+	Parse(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	ParsePlain(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	ParseXHTML(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	ParseReader(ctx context.Context, input io.Reader, opts ...RequestOption) (io.ReadCloser, error)
+	ParseTo(ctx context.Context, w io.Writer, input io.Reader, opts ...RequestOption) (int64, error)
+	ParseLimited(ctx context.Context, input io.Reader, limit int, opts ...RequestOption) (body string, truncated bool, err error)
+	ParsePages(ctx context.Context, input io.Reader, opts ...RequestOption) ([]Page, error)
+	ParseRecursive(ctx context.Context, input io.Reader, opts ...RequestOption) ([]string, error)
+
+	// Metadata.
+	Meta(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	MetaField(ctx context.Context, input io.Reader, field string, opts ...RequestOption) (string, error)
+	MetaFieldValues(ctx context.Context, input io.Reader, field string, opts ...RequestOption) ([]string, error)
+	MetaJSON(ctx context.Context, input io.Reader, opts ...RequestOption) (Metadata, error)
+	MetaXMP(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	MetaRecursive(ctx context.Context, input io.Reader, opts ...RequestOption) ([]map[string][]string, error)
+	MetaRecursiveType(ctx context.Context, input io.Reader, contentType string, opts ...RequestOption) ([]map[string][]string, error)
+	RecursiveMetadata(ctx context.Context, input io.Reader, format ContentFormat, opts ...RequestOption) ([]Metadata, error)
+	RecursiveMetadataTree(ctx context.Context, input io.Reader, format ContentFormat, opts ...RequestOption) (*DocumentNode, error)
+	Email(ctx context.Context, input io.Reader, opts ...RequestOption) (*EmailResult, error)
+
+	// Detection, language and translation.
+	Detect(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	DetectReader(ctx context.Context, input io.Reader, filename string, opts ...RequestOption) (mimeType string, source DetectSource, err error)
+	DetectEncoding(ctx context.Context, input io.Reader, opts ...RequestOption) (Encoding, error)
+	Language(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error)
+	LanguageString(ctx context.Context, input string, opts ...RequestOption) (string, error)
+	Translate(ctx context.Context, input io.Reader, t Translator, src, dst string, opts ...RequestOption) (string, error)
+	TranslateAuto(ctx context.Context, input io.Reader, t Translator, dst string, opts ...RequestOption) (string, error)
+
+	// Embedded documents.
+	Unpack(ctx context.Context, input io.Reader, opts ...RequestOption) (map[string][]byte, error)
+	UnpackAll(ctx context.Context, input io.Reader, opts ...RequestOption) (map[string][]byte, error)
+
+	// Pipes.
+	AsyncParse(ctx context.Context, tuples []FetchEmitTuple, opts ...RequestOption) (*AsyncResult, error)
+
+	// Server information.
+	Version(ctx context.Context, opts ...RequestOption) (string, error)
+	ServerVersion(ctx context.Context, opts ...RequestOption) (ParsedVersion, error)
+	Ping(ctx context.Context, opts ...RequestOption) error
+	IsAlive(ctx context.Context, opts ...RequestOption) bool
+	Status(ctx context.Context, opts ...RequestOption) (*ServerStatus, error)
+	Parsers(ctx context.Context, opts ...RequestOption) (*Parser, error)
+	Detectors(ctx context.Context, opts ...RequestOption) (*Detector, error)
+	MIMETypes(ctx context.Context, opts ...RequestOption) (map[string]MIMEType, error)
+	ProbeOCR(ctx context.Context, langs []string, opts ...RequestOption) (*OCRSupport, error)
+}
+
+var _ TikaClient = (*Client)(nil)
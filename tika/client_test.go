@@ -0,0 +1,57 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+// upperClient is a decorator that upper cases the text parsed by the
+// TikaClient it wraps.
+type upperClient struct {
+	TikaClient
+}
+
+func (c upperClient) Parse(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	body, err := c.TikaClient.Parse(ctx, input, opts...)
+	return strings.ToUpper(body), err
+}
+
+// fakeClient is a TikaClient that parses every document as its own bytes.
+type fakeClient struct {
+	TikaClient
+}
+
+func (fakeClient) Parse(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	b, err := ioutil.ReadAll(input)
+	return string(b), err
+}
+
+func TestTikaClient(t *testing.T) {
+	var c TikaClient = upperClient{fakeClient{}}
+	got, err := c.Parse(context.Background(), strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if want := "HELLO"; got != want {
+		t.Errorf("Parse = %q, want %q", got, want)
+	}
+}
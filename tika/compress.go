@@ -0,0 +1,127 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// minGzipSize is the smallest request body that is compressed. Compressing
+// smaller bodies isn't worth the overhead.
+const minGzipSize = 1024
+
+// gzipRequest compresses the body of req, unless it is known to be small.
+func gzipRequest(req *http.Request) {
+	if req.Body == nil || req.Body == http.NoBody || (req.ContentLength > 0 && req.ContentLength < minGzipSize) {
+		return
+	}
+	req.Body = gzipReader(req.Body)
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return gzipReader(body), nil
+		}
+	}
+	req.ContentLength = -1
+	req.Header.Set("Content-Encoding", "gzip")
+}
+
+// gzipReader returns a reader of r compressed with gzip. r is closed once it
+// has been read, or when the returned reader is closed. Compression only
+// starts on the first Read, so a request that is dropped before it is sent
+// doesn't leave a goroutine behind.
+func gzipReader(r io.ReadCloser) io.ReadCloser {
+	return &gzipPipe{r: r}
+}
+
+// gzipPipe compresses r in a goroutine that is started by the first Read.
+type gzipPipe struct {
+	r    io.ReadCloser
+	once sync.Once
+	pr   *io.PipeReader
+}
+
+func (p *gzipPipe) start() {
+	pr, pw := io.Pipe()
+	p.pr = pr
+	go func() {
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, p.r)
+		if err == nil {
+			err = zw.Close()
+		}
+		p.r.Close()
+		pw.CloseWithError(err)
+	}()
+}
+
+func (p *gzipPipe) Read(b []byte) (int, error) {
+	p.once.Do(p.start)
+	return p.pr.Read(b)
+}
+
+func (p *gzipPipe) Close() error {
+	unread := false
+	p.once.Do(func() {
+		// Never read, so there is nothing to compress.
+		p.pr, _ = io.Pipe()
+		p.pr.Close()
+		unread = true
+	})
+	if unread {
+		return p.r.Close()
+	}
+	return p.pr.Close()
+}
+
+// gunzipResponse decompresses the body of resp if the server compressed it.
+func gunzipResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		// An empty body.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody is a decompressed response body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// gzipServer responds with the request body, decompressing the request and
+// compressing the response if the client asks for it.
+func gzipServer(t *testing.T, gotEncoding *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*gotEncoding = r.Header.Get("Content-Encoding")
+		var body io.Reader = r.Body
+		if *gotEncoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("server got invalid gzip body: %v", err)
+				return
+			}
+			body = zr
+		}
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			t.Errorf("server got error reading body: %v", err)
+			return
+		}
+		if r.Header.Get("Accept-Encoding") == "gzip" {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			zw.Write(b)
+			zw.Close()
+			return
+		}
+		w.Write(b)
+	}))
+}
+
+func TestWithCompression(t *testing.T) {
+	var encoding string
+	ts := gzipServer(t, &encoding)
+	defer ts.Close()
+	// Disable the transport's own decompression to check the Client's.
+	hc := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	c := NewClientWithOptions(ts.URL, WithHTTPClient(hc), WithCompression())
+
+	tests := []struct {
+		name         string
+		body         string
+		unknownLen   bool
+		wantEncoding string
+	}{
+		{name: "large body", body: strings.Repeat("a", 2*minGzipSize), wantEncoding: "gzip"},
+		{name: "small body", body: "small"},
+		{name: "unknown length", body: "small", unknownLen: true, wantEncoding: "gzip"},
+	}
+	for _, test := range tests {
+		var input io.Reader = strings.NewReader(test.body)
+		if test.unknownLen {
+			input = ioutil.NopCloser(input)
+		}
+		want := test.body
+		got, err := c.Parse(context.Background(), input)
+		if err != nil {
+			t.Errorf("%s: Parse got error: %v", test.name, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: Parse got %d bytes, want %d", test.name, len(got), len(want))
+		}
+		if encoding != test.wantEncoding {
+			t.Errorf("%s: Parse sent Content-Encoding %q, want %q", test.name, encoding, test.wantEncoding)
+		}
+	}
+}
+
+func TestWithCompressionRetry(t *testing.T) {
+	var encoding string
+	inner := gzipServer(t, &encoding)
+	defer inner.Close()
+	failed := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rp, _ := http.NewRequest(r.Method, inner.URL, r.Body)
+		rp.Header = r.Header
+		resp, err := http.DefaultTransport.RoundTrip(rp)
+		if err != nil {
+			t.Errorf("error proxying request: %v", err)
+			return
+		}
+		defer resp.Body.Close()
+		w.Header().Set("Content-Encoding", resp.Header.Get("Content-Encoding"))
+		io.Copy(w, resp.Body)
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithCompression(), WithRetry(RetryPolicy{InitialBackoff: 1}))
+	want := strings.Repeat("b", 2*minGzipSize)
+	got, err := c.Parse(context.Background(), strings.NewReader(want))
+	if err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Parse after a retry got %d bytes, want %d", len(got), len(want))
+	}
+}
+
+// closeRecorder records whether it was read or closed.
+type closeRecorder struct {
+	io.Reader
+	read, closed bool
+}
+
+func (r *closeRecorder) Read(b []byte) (int, error) {
+	r.read = true
+	return r.Reader.Read(b)
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestGzipReader(t *testing.T) {
+	// A body that is closed without being read is never compressed.
+	r := &closeRecorder{Reader: strings.NewReader("unread")}
+	z := gzipReader(r)
+	if err := z.Close(); err != nil {
+		t.Errorf("Close got error: %v", err)
+	}
+	if r.read || !r.closed {
+		t.Errorf("unread body: read = %t, closed = %t, want false and true", r.read, r.closed)
+	}
+	if _, err := z.Read(make([]byte, 1)); err == nil {
+		t.Errorf("Read after Close got no error, want an error")
+	}
+
+	r = &closeRecorder{Reader: strings.NewReader(strings.Repeat("compress me ", 100))}
+	zr, err := gzip.NewReader(gzipReader(r))
+	if err != nil {
+		t.Fatalf("gzip.NewReader got error: %v", err)
+	}
+	got, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("error reading compressed body: %v", err)
+	}
+	if want := strings.Repeat("compress me ", 100); string(got) != want {
+		t.Errorf("decompressed body = %q, want %q", got, want)
+	}
+	if !r.closed {
+		t.Errorf("body not closed after it was read")
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config builds Tika Server configuration files (tika-config.xml),
+// for use with tika.WithConfigFile. A Config can configure parsers,
+// detectors, the translator, metadata filters and tika-pipes.
+//
+//	cfg := &config.Config{
+//		Fetchers: []config.Fetcher{config.FileSystemFetcher{Name: "in", BasePath: "/data/in"}},
+//		Emitters: []config.Emitter{config.FileSystemEmitter{Name: "out", BasePath: "/data/out"}},
+//	}
+//	if err := cfg.WriteFile("tika-config.xml"); err != nil {
+//		log.Fatal(err)
+//	}
+//	s, err := tika.NewServer(jar, "", tika.WithConfigFile("tika-config.xml"))
+package config
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+)
+
+// A Config is a Tika Server configuration.
+type Config struct {
+	// Parsers and Detectors configure the parsers and detectors, for
+	// example to disable some of them.
+	Parsers   []Parser
+	Detectors []Detector
+	// Translator is the Java class of the translator used by
+	// tika.Client.Translate, such as
+	// "org.apache.tika.language.translate.impl.GoogleTranslator".
+	Translator string
+	// MetadataFilters filter the metadata of every document, in order.
+	MetadataFilters []MetadataFilter
+	// Fetchers and Emitters are the tika-pipes fetchers and emitters, which
+	// are used by tika.Client.AsyncParse. They require Tika 2.x.
+	Fetchers []Fetcher
+	Emitters []Emitter
+}
+
+// Marshal returns c as a tika-config.xml document.
+func (c *Config) Marshal() ([]byte, error) {
+	p := xmlProperties{}
+	if len(c.Parsers) > 0 {
+		p.Parsers = &xmlParsers{}
+		for _, x := range c.Parsers {
+			p.Parsers.Parsers = append(p.Parsers.Parsers, x.xml())
+		}
+	}
+	if len(c.Detectors) > 0 {
+		p.Detectors = &xmlDetectors{}
+		for _, d := range c.Detectors {
+			p.Detectors.Detectors = append(p.Detectors.Detectors, d.xml())
+		}
+	}
+	if c.Translator != "" {
+		p.Translator = &xmlClass{Class: c.Translator}
+	}
+	if len(c.MetadataFilters) > 0 {
+		p.MetadataFilters = &xmlMetadataFilters{}
+		for _, f := range c.MetadataFilters {
+			p.MetadataFilters.Filters = append(p.MetadataFilters.Filters, f.metadataFilter())
+		}
+	}
+	if len(c.Fetchers) > 0 {
+		p.Fetchers = &xmlFetchers{}
+		for _, f := range c.Fetchers {
+			p.Fetchers.Fetchers = append(p.Fetchers.Fetchers, f.fetcher().xml())
+		}
+	}
+	if len(c.Emitters) > 0 {
+		p.Emitters = &xmlEmitters{}
+		for _, e := range c.Emitters {
+			p.Emitters.Emitters = append(p.Emitters.Emitters, e.emitter().xml())
+		}
+	}
+	b, err := xml.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// WriteFile writes c to path as a tika-config.xml document.
+func (c *Config) WriteFile(path string) error {
+	b, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// A component is a configured Java class, such as a fetcher.
+type component struct {
+	class  string
+	params []param
+}
+
+// A param is a parameter of a component. Params with an empty value are
+// omitted.
+type param struct {
+	name, value string
+}
+
+func (c component) xml() xmlComponent {
+	x := xmlComponent{Class: c.class}
+	for _, p := range c.params {
+		if p.value != "" {
+			x.Params = append(x.Params, xmlParam{XMLName: xml.Name{Local: p.name}, Value: p.value})
+		}
+	}
+	return x
+}
+
+type xmlProperties struct {
+	XMLName         xml.Name            `xml:"properties"`
+	Parsers         *xmlParsers         `xml:"parsers"`
+	Detectors       *xmlDetectors       `xml:"detectors"`
+	Translator      *xmlClass           `xml:"translator"`
+	MetadataFilters *xmlMetadataFilters `xml:"metadataFilters"`
+	Fetchers        *xmlFetchers        `xml:"fetchers"`
+	Emitters        *xmlEmitters        `xml:"emitters"`
+}
+
+type xmlFetchers struct {
+	Fetchers []xmlComponent `xml:"fetcher"`
+}
+
+type xmlEmitters struct {
+	Emitters []xmlComponent `xml:"emitter"`
+}
+
+type xmlComponent struct {
+	Class  string     `xml:"class,attr"`
+	Params []xmlParam `xml:"params>param"`
+}
+
+type xmlParam struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
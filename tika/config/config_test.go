@@ -0,0 +1,136 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalPipes(t *testing.T) {
+	c := &Config{
+		Fetchers: []Fetcher{
+			FileSystemFetcher{Name: "fsf", BasePath: "/data/in", ExtractFileSystemMetadata: true},
+			HTTPFetcher{Name: "http", ConnectTimeout: 2 * time.Second},
+			S3Fetcher{Name: "s3", Region: "us-east-1", Bucket: "docs", CredentialsProvider: "instance"},
+			CustomFetcher{Name: "custom", Class: "com.example.Fetcher", Params: map[string]string{"b": "2", "a": "1"}},
+		},
+		Emitters: []Emitter{
+			FileSystemEmitter{Name: "fse", BasePath: "/data/out", OnExists: "replace"},
+			OpenSearchEmitter{Name: "os", URL: "https://localhost:9200/docs", CommitWithin: 100},
+		},
+	}
+	b, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal got error: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<properties>
+  <fetchers>
+    <fetcher class="org.apache.tika.pipes.fetcher.fs.FileSystemFetcher">
+      <params>
+        <name>fsf</name>
+        <basePath>/data/in</basePath>
+        <extractFileSystemMetadata>true</extractFileSystemMetadata>
+      </params>
+    </fetcher>
+    <fetcher class="org.apache.tika.pipes.fetcher.http.HttpFetcher">
+      <params>
+        <name>http</name>
+        <connectTimeout>2000</connectTimeout>
+      </params>
+    </fetcher>
+    <fetcher class="org.apache.tika.pipes.fetcher.s3.S3Fetcher">
+      <params>
+        <name>s3</name>
+        <region>us-east-1</region>
+        <bucket>docs</bucket>
+        <credentialsProvider>instance</credentialsProvider>
+      </params>
+    </fetcher>
+    <fetcher class="com.example.Fetcher">
+      <params>
+        <name>custom</name>
+        <a>1</a>
+        <b>2</b>
+      </params>
+    </fetcher>
+  </fetchers>
+  <emitters>
+    <emitter class="org.apache.tika.pipes.emitter.fs.FileSystemEmitter">
+      <params>
+        <name>fse</name>
+        <basePath>/data/out</basePath>
+        <onExists>replace</onExists>
+      </params>
+    </emitter>
+    <emitter class="org.apache.tika.pipes.emitter.opensearch.OpenSearchEmitter">
+      <params>
+        <name>os</name>
+        <openSearchUrl>https://localhost:9200/docs</openSearchUrl>
+        <commitWithin>100</commitWithin>
+      </params>
+    </emitter>
+  </emitters>
+</properties>
+`
+	if got := string(b); got != want {
+		t.Errorf("Marshal got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMarshalEmpty(t *testing.T) {
+	b, err := (&Config{}).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal got error: %v", err)
+	}
+	if got, want := strings.TrimSpace(string(b)), `<?xml version="1.0" encoding="UTF-8"?>`+"\n<properties></properties>"; got != want {
+		t.Errorf("Marshal got %q, want %q", got, want)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	c := &Config{Fetchers: []Fetcher{FileSystemFetcher{Name: "fsf"}}}
+	path := filepath.Join(dir, "tika-config.xml")
+	if err := c.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile got error: %v", err)
+	}
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading config: %v", err)
+	}
+	want, _ := c.Marshal()
+	if string(got) != string(want) {
+		t.Errorf("WriteFile wrote %q, want %q", got, want)
+	}
+}
+
+func TestTuple(t *testing.T) {
+	got := Tuple(FileSystemFetcher{Name: "in"}, OpenSearchEmitter{Name: "out"}, "a.pdf")
+	if got.Fetcher != "in" || got.Emitter != "out" || got.FetchKey != "a.pdf" || got.EmitKey != "a.pdf" {
+		t.Errorf("Tuple got %+v, want fetcher in, emitter out and keys a.pdf", got)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// Java classes of the default parser and detector, which combine all the
+// parsers or detectors on the server's classpath.
+const (
+	DefaultParser   = "org.apache.tika.parser.DefaultParser"
+	DefaultDetector = "org.apache.tika.detect.DefaultDetector"
+)
+
+// A Parser configures a parser. To disable parsers, configure DefaultParser
+// with them in Exclude.
+type Parser struct {
+	// Class is the Java class of the parser.
+	Class string
+	// Exclude are the classes of the parsers a composite parser, such as
+	// DefaultParser, doesn't use.
+	Exclude []string
+	// MIMETypes, if set, are the only types the parser is used for, and
+	// ExcludeMIMETypes the types it isn't used for.
+	MIMETypes        []string
+	ExcludeMIMETypes []string
+	Params           []Param
+}
+
+// A Param is a parameter of a parser, such as the OCR timeout of the
+// TesseractOCRParser. Type is the Java type of the value, such as "int",
+// "bool" or "string".
+type Param struct {
+	Name, Type, Value string
+}
+
+// IntParam returns an int Param.
+func IntParam(name string, v int) Param {
+	return Param{Name: name, Type: "int", Value: strconv.Itoa(v)}
+}
+
+// BoolParam returns a bool Param.
+func BoolParam(name string, v bool) Param {
+	return Param{Name: name, Type: "bool", Value: strconv.FormatBool(v)}
+}
+
+// StringParam returns a string Param.
+func StringParam(name, v string) Param {
+	return Param{Name: name, Type: "string", Value: v}
+}
+
+// TesseractOCR returns the configuration of the TesseractOCRParser with the
+// given timeout, rounded up to a whole number of seconds, and Tesseract
+// languages, such as "eng+fra". Empty values keep the server defaults.
+func TesseractOCR(timeout time.Duration, language string) Parser {
+	p := Parser{Class: "org.apache.tika.parser.ocr.TesseractOCRParser"}
+	if timeout > 0 {
+		p.Params = append(p.Params, IntParam("timeoutSeconds", int((timeout+time.Second-1)/time.Second)))
+	}
+	if language != "" {
+		p.Params = append(p.Params, StringParam("language", language))
+	}
+	return p
+}
+
+// A Detector configures a detector. To disable detectors, configure
+// DefaultDetector with them in Exclude.
+type Detector struct {
+	// Class is the Java class of the detector.
+	Class string
+	// Exclude are the classes of the detectors a composite detector, such as
+	// DefaultDetector, doesn't use.
+	Exclude []string
+}
+
+// A MetadataFilter filters the metadata Tika returns. Metadata filters
+// require Tika 2.x.
+type MetadataFilter interface {
+	metadataFilter() xmlMetadataFilter
+}
+
+// IncludeFields is a MetadataFilter that removes all metadata fields but the
+// given ones.
+type IncludeFields []string
+
+func (f IncludeFields) metadataFilter() xmlMetadataFilter {
+	return xmlMetadataFilter{
+		Class:  "org.apache.tika.metadata.filter.IncludeFieldMetadataFilter",
+		Params: &xmlFilterParams{Include: &xmlFields{Fields: f}},
+	}
+}
+
+// ExcludeFields is a MetadataFilter that removes the given metadata fields.
+type ExcludeFields []string
+
+func (f ExcludeFields) metadataFilter() xmlMetadataFilter {
+	return xmlMetadataFilter{
+		Class:  "org.apache.tika.metadata.filter.ExcludeFieldMetadataFilter",
+		Params: &xmlFilterParams{Exclude: &xmlFields{Fields: f}},
+	}
+}
+
+// CustomMetadataFilter is a MetadataFilter of any class.
+type CustomMetadataFilter struct {
+	Class  string
+	Params []Param
+}
+
+func (f CustomMetadataFilter) metadataFilter() xmlMetadataFilter {
+	x := xmlMetadataFilter{Class: f.Class}
+	if len(f.Params) > 0 {
+		x.Params = &xmlFilterParams{Params: typedParams(f.Params)}
+	}
+	return x
+}
+
+func (p Parser) xml() xmlParser {
+	x := xmlParser{
+		Class:       p.Class,
+		Exclude:     classes(p.Exclude),
+		MIME:        p.MIMETypes,
+		MIMEExclude: p.ExcludeMIMETypes,
+	}
+	if len(p.Params) > 0 {
+		x.Params = &xmlParserParams{Params: typedParams(p.Params)}
+	}
+	return x
+}
+
+func (d Detector) xml() xmlDetector {
+	return xmlDetector{Class: d.Class, Exclude: classes(d.Exclude)}
+}
+
+func classes(names []string) []xmlClass {
+	var r []xmlClass
+	for _, n := range names {
+		r = append(r, xmlClass{Class: n})
+	}
+	return r
+}
+
+func typedParams(params []Param) []xmlTypedParam {
+	var r []xmlTypedParam
+	for _, p := range params {
+		r = append(r, xmlTypedParam{Name: p.Name, Type: p.Type, Value: p.Value})
+	}
+	return r
+}
+
+type xmlParsers struct {
+	Parsers []xmlParser `xml:"parser"`
+}
+
+type xmlParser struct {
+	Class       string           `xml:"class,attr"`
+	Exclude     []xmlClass       `xml:"parser-exclude"`
+	MIME        []string         `xml:"mime"`
+	MIMEExclude []string         `xml:"mime-exclude"`
+	Params      *xmlParserParams `xml:"params"`
+}
+
+type xmlParserParams struct {
+	Params []xmlTypedParam `xml:"param"`
+}
+
+type xmlDetectors struct {
+	Detectors []xmlDetector `xml:"detector"`
+}
+
+type xmlDetector struct {
+	Class   string     `xml:"class,attr"`
+	Exclude []xmlClass `xml:"detector-exclude"`
+}
+
+type xmlMetadataFilters struct {
+	Filters []xmlMetadataFilter `xml:"metadataFilter"`
+}
+
+type xmlMetadataFilter struct {
+	Class  string           `xml:"class,attr"`
+	Params *xmlFilterParams `xml:"params"`
+}
+
+type xmlFilterParams struct {
+	Include *xmlFields      `xml:"include"`
+	Exclude *xmlFields      `xml:"exclude"`
+	Params  []xmlTypedParam `xml:"param"`
+}
+
+type xmlFields struct {
+	Fields []string `xml:"field"`
+}
+
+type xmlClass struct {
+	Class string `xml:"class,attr"`
+}
+
+type xmlTypedParam struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMarshalParsers(t *testing.T) {
+	c := &Config{
+		Parsers: []Parser{
+			{
+				Class:            DefaultParser,
+				Exclude:          []string{"org.apache.tika.parser.executable.ExecutableParser"},
+				ExcludeMIMETypes: []string{"image/jpeg"},
+			},
+			TesseractOCR(90*time.Second+time.Millisecond, "eng+fra"),
+			{
+				Class:     "org.apache.tika.parser.pdf.PDFParser",
+				MIMETypes: []string{"application/pdf"},
+				Params:    []Param{BoolParam("extractInlineImages", true), IntParam("ocrDPI", 300)},
+			},
+		},
+		Detectors: []Detector{
+			{Class: DefaultDetector, Exclude: []string{"org.apache.tika.detect.OverrideDetector"}},
+		},
+		Translator: "org.apache.tika.language.translate.impl.GoogleTranslator",
+		MetadataFilters: []MetadataFilter{
+			ExcludeFields{"X-TIKA:Parsed-By"},
+			IncludeFields{"dc:title", "Content-Type"},
+			CustomMetadataFilter{Class: "com.example.Filter", Params: []Param{StringParam("mode", "strict")}},
+		},
+	}
+	b, err := c.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal got error: %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<properties>
+  <parsers>
+    <parser class="org.apache.tika.parser.DefaultParser">
+      <parser-exclude class="org.apache.tika.parser.executable.ExecutableParser"></parser-exclude>
+      <mime-exclude>image/jpeg</mime-exclude>
+    </parser>
+    <parser class="org.apache.tika.parser.ocr.TesseractOCRParser">
+      <params>
+        <param name="timeoutSeconds" type="int">91</param>
+        <param name="language" type="string">eng+fra</param>
+      </params>
+    </parser>
+    <parser class="org.apache.tika.parser.pdf.PDFParser">
+      <mime>application/pdf</mime>
+      <params>
+        <param name="extractInlineImages" type="bool">true</param>
+        <param name="ocrDPI" type="int">300</param>
+      </params>
+    </parser>
+  </parsers>
+  <detectors>
+    <detector class="org.apache.tika.detect.DefaultDetector">
+      <detector-exclude class="org.apache.tika.detect.OverrideDetector"></detector-exclude>
+    </detector>
+  </detectors>
+  <translator class="org.apache.tika.language.translate.impl.GoogleTranslator"></translator>
+  <metadataFilters>
+    <metadataFilter class="org.apache.tika.metadata.filter.ExcludeFieldMetadataFilter">
+      <params>
+        <exclude>
+          <field>X-TIKA:Parsed-By</field>
+        </exclude>
+      </params>
+    </metadataFilter>
+    <metadataFilter class="org.apache.tika.metadata.filter.IncludeFieldMetadataFilter">
+      <params>
+        <include>
+          <field>dc:title</field>
+          <field>Content-Type</field>
+        </include>
+      </params>
+    </metadataFilter>
+    <metadataFilter class="com.example.Filter">
+      <params>
+        <param name="mode" type="string">strict</param>
+      </params>
+    </metadataFilter>
+  </metadataFilters>
+</properties>
+`
+	if got := string(b); got != want {
+		t.Errorf("Marshal got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTesseractOCRDefaults(t *testing.T) {
+	if p := TesseractOCR(0, ""); len(p.Params) != 0 {
+		t.Errorf("TesseractOCR(0, \"\") got params %v, want none", p.Params)
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/go-tika/tika"
+)
+
+// A Fetcher reads documents for tika-pipes. See Config.
+type Fetcher interface {
+	fetcher() component
+	// FetcherName returns the name the fetcher is referenced by in a
+	// tika.FetchEmitTuple.
+	FetcherName() string
+}
+
+// An Emitter writes the results of tika-pipes. See Config.
+type Emitter interface {
+	emitter() component
+	// EmitterName returns the name the emitter is referenced by in a
+	// tika.FetchEmitTuple.
+	EmitterName() string
+}
+
+// Tuple returns a tika.FetchEmitTuple that fetches key with f and emits the
+// result with e under the same key.
+func Tuple(f Fetcher, e Emitter, key string) tika.FetchEmitTuple {
+	return tika.FetchEmit(f.FetcherName(), e.EmitterName(), key)
+}
+
+// boolParam returns "true" if v is true, and "" (omitting the param)
+// otherwise.
+func boolParam(v bool) string {
+	if v {
+		return "true"
+	}
+	return ""
+}
+
+// intParam returns v as a string, or "" (omitting the param) if v is 0.
+func intParam(v int) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(v)
+}
+
+// millisParam returns d in milliseconds, or "" (omitting the param) if d is
+// 0.
+func millisParam(d time.Duration) string {
+	return intParam(int(d / time.Millisecond))
+}
+
+// FileSystemFetcher fetches documents from a directory on the server.
+type FileSystemFetcher struct {
+	Name string
+	// BasePath is the directory fetch keys are relative to.
+	BasePath string
+	// ExtractFileSystemMetadata adds the file's times and size to its
+	// metadata.
+	ExtractFileSystemMetadata bool
+}
+
+// FetcherName implements Fetcher.
+func (f FileSystemFetcher) FetcherName() string { return f.Name }
+
+func (f FileSystemFetcher) fetcher() component {
+	return component{
+		class: "org.apache.tika.pipes.fetcher.fs.FileSystemFetcher",
+		params: []param{
+			{"name", f.Name},
+			{"basePath", f.BasePath},
+			{"extractFileSystemMetadata", boolParam(f.ExtractFileSystemMetadata)},
+		},
+	}
+}
+
+// HTTPFetcher fetches documents by URL, using the URL as the fetch key.
+type HTTPFetcher struct {
+	Name string
+	// Timeouts for connecting, for the whole request and between packets.
+	ConnectTimeout, RequestTimeout, SocketTimeout time.Duration
+	// MaxConnections and MaxConnectionsPerRoute limit the open connections.
+	MaxConnections, MaxConnectionsPerRoute int
+}
+
+// FetcherName implements Fetcher.
+func (f HTTPFetcher) FetcherName() string { return f.Name }
+
+func (f HTTPFetcher) fetcher() component {
+	return component{
+		class: "org.apache.tika.pipes.fetcher.http.HttpFetcher",
+		params: []param{
+			{"name", f.Name},
+			{"connectTimeout", millisParam(f.ConnectTimeout)},
+			{"requestTimeout", millisParam(f.RequestTimeout)},
+			{"socketTimeout", millisParam(f.SocketTimeout)},
+			{"maxConnections", intParam(f.MaxConnections)},
+			{"maxConnectionsPerRoute", intParam(f.MaxConnectionsPerRoute)},
+		},
+	}
+}
+
+// S3Fetcher fetches documents from an Amazon S3 bucket.
+type S3Fetcher struct {
+	Name   string
+	Region string
+	Bucket string
+	// Prefix is prepended to fetch keys.
+	Prefix string
+	// CredentialsProvider is "profile" to use the named Profile, or
+	// "instance" to use the credentials of the EC2 instance.
+	CredentialsProvider string
+	Profile             string
+	// ExtractUserMetadata adds the object's user metadata to its metadata.
+	ExtractUserMetadata bool
+}
+
+// FetcherName implements Fetcher.
+func (f S3Fetcher) FetcherName() string { return f.Name }
+
+func (f S3Fetcher) fetcher() component {
+	return component{
+		class: "org.apache.tika.pipes.fetcher.s3.S3Fetcher",
+		params: []param{
+			{"name", f.Name},
+			{"region", f.Region},
+			{"bucket", f.Bucket},
+			{"prefix", f.Prefix},
+			{"credentialsProvider", f.CredentialsProvider},
+			{"profile", f.Profile},
+			{"extractUserMetadata", boolParam(f.ExtractUserMetadata)},
+		},
+	}
+}
+
+// FileSystemEmitter writes results as JSON files to a directory on the
+// server.
+type FileSystemEmitter struct {
+	Name string
+	// BasePath is the directory emit keys are relative to.
+	BasePath string
+	// FileExtension is appended to emit keys. The server default is "json".
+	FileExtension string
+	// OnExists is "skip", "replace" or "exception", telling the emitter what
+	// to do if the output file exists.
+	OnExists string
+}
+
+// EmitterName implements Emitter.
+func (e FileSystemEmitter) EmitterName() string { return e.Name }
+
+func (e FileSystemEmitter) emitter() component {
+	return component{
+		class: "org.apache.tika.pipes.emitter.fs.FileSystemEmitter",
+		params: []param{
+			{"name", e.Name},
+			{"basePath", e.BasePath},
+			{"fileExtension", e.FileExtension},
+			{"onExists", e.OnExists},
+		},
+	}
+}
+
+// OpenSearchEmitter indexes results in an OpenSearch index.
+type OpenSearchEmitter struct {
+	Name string
+	// URL is the URL of the index, for example
+	// https://localhost:9200/my-index.
+	URL string
+	// IDField is the document field that holds the emit key.
+	IDField string
+	// AttachmentStrategy is "separate_documents" or "parent_child", telling
+	// the emitter how to index embedded documents.
+	AttachmentStrategy string
+	// UpdateStrategy is "overwrite" or "upsert".
+	UpdateStrategy string
+	// CommitWithin is the maximum number of documents to buffer.
+	CommitWithin       int
+	UserName, Password string
+}
+
+// EmitterName implements Emitter.
+func (e OpenSearchEmitter) EmitterName() string { return e.Name }
+
+func (e OpenSearchEmitter) emitter() component {
+	return component{
+		class: "org.apache.tika.pipes.emitter.opensearch.OpenSearchEmitter",
+		params: []param{
+			{"name", e.Name},
+			{"openSearchUrl", e.URL},
+			{"idField", e.IDField},
+			{"attachmentStrategy", e.AttachmentStrategy},
+			{"updateStrategy", e.UpdateStrategy},
+			{"commitWithin", intParam(e.CommitWithin)},
+			{"userName", e.UserName},
+			{"password", e.Password},
+		},
+	}
+}
+
+// CustomFetcher is a fetcher of any class, such as one from a custom JAR.
+type CustomFetcher struct {
+	Name   string
+	Class  string
+	Params map[string]string
+}
+
+// FetcherName implements Fetcher.
+func (f CustomFetcher) FetcherName() string { return f.Name }
+
+func (f CustomFetcher) fetcher() component {
+	return customComponent(f.Class, f.Name, f.Params)
+}
+
+// CustomEmitter is an emitter of any class, such as one from a custom JAR.
+type CustomEmitter struct {
+	Name   string
+	Class  string
+	Params map[string]string
+}
+
+// EmitterName implements Emitter.
+func (e CustomEmitter) EmitterName() string { return e.Name }
+
+func (e CustomEmitter) emitter() component {
+	return customComponent(e.Class, e.Name, e.Params)
+}
+
+// customComponent returns a component with the params sorted by name, after
+// the name param.
+func customComponent(class, name string, params map[string]string) component {
+	c := component{class: class, params: []param{{"name", name}}}
+	var keys []string
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		c.params = append(c.params, param{k, params[k]})
+	}
+	return c
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// taskTimeoutHeader tells Tika Server how long it may spend on a request
+// before aborting it. It is only enforced when the server runs in child mode
+// (see WithSpawnChild).
+const taskTimeoutHeader = "X-Tika-Timeout-Millis"
+
+// WithParseTimeout sets how long the server may spend parsing the document
+// before aborting, rounded up to a millisecond. It overrides the default of
+// the server (see WithTaskTimeout) and the timeout derived from the deadline
+// of the request context.
+func WithParseTimeout(d time.Duration) RequestOption {
+	return setHeader(taskTimeoutHeader, strconv.FormatInt(ceilMillis(d), 10))
+}
+
+// WithDeadlinePropagation sets whether the Client passes the remaining time
+// until the deadline of the request context to the server as its task
+// timeout, which it does by default. The server then stops parsing documents
+// the Client has given up on instead of continuing to use CPU.
+func WithDeadlinePropagation(enabled bool) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.noDeadline = !enabled
+	}
+}
+
+// setTaskTimeout sets the task timeout of req to the time left until the
+// deadline of ctx, if it has one.
+func setTaskTimeout(ctx context.Context, req *http.Request) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	ms := ceilMillis(time.Until(deadline))
+	if ms < 1 {
+		ms = 1
+	}
+	req.Header.Set(taskTimeoutHeader, strconv.FormatInt(ms, 10))
+}
+
+// ceilMillis returns d in milliseconds, rounded up.
+func ceilMillis(d time.Duration) int64 {
+	return int64((d + time.Millisecond - 1) / time.Millisecond)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeadlinePropagation(t *testing.T) {
+	var got []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(taskTimeoutHeader))
+		if len(got) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("text"))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		opts    []ClientOption
+		reqOpts []RequestOption
+		timeout time.Duration
+		check   func(ms []int64) bool
+	}{
+		{
+			name:    "deadline",
+			timeout: time.Minute,
+			opts:    []ClientOption{WithRetry(RetryPolicy{InitialBackoff: 10 * time.Millisecond})},
+			check: func(ms []int64) bool {
+				// The retry has less time left than the first attempt.
+				return len(ms) == 2 && ms[0] <= 60000 && ms[0] > 50000 && ms[1] <= ms[0]
+			},
+		},
+		{
+			name:    "explicit",
+			timeout: time.Minute,
+			reqOpts: []RequestOption{WithParseTimeout(1500 * time.Microsecond)},
+			check:   func(ms []int64) bool { return len(ms) == 1 && ms[0] == 2 },
+		},
+		{
+			name:    "disabled",
+			timeout: time.Minute,
+			opts:    []ClientOption{WithDeadlinePropagation(false)},
+			check:   func(ms []int64) bool { return len(ms) == 1 && ms[0] == -1 },
+		},
+		{
+			name:  "no deadline",
+			check: func(ms []int64) bool { return len(ms) == 1 && ms[0] == -1 },
+		},
+	}
+	for _, test := range tests {
+		got = nil
+		ctx := context.Background()
+		if test.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, test.timeout)
+			defer cancel()
+		}
+		c := NewClientWithOptions(ts.URL, test.opts...)
+		c.Parse(ctx, strings.NewReader("input"), test.reqOpts...)
+		var ms []int64
+		for _, h := range got {
+			n := int64(-1)
+			if h != "" {
+				var err error
+				if n, err = strconv.ParseInt(h, 10, 64); err != nil {
+					t.Errorf("%s: invalid %s header %q", test.name, taskTimeoutHeader, h)
+				}
+			}
+			ms = append(ms, n)
+		}
+		if !test.check(ms) {
+			t.Errorf("%s: got %s headers %q", test.name, taskTimeoutHeader, got)
+		}
+	}
+}
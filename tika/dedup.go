@@ -0,0 +1,140 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"sync"
+)
+
+// A SeenStore records the digests of the documents a Batch has processed, so
+// documents seen in an earlier run can be skipped. Implementations must be
+// safe for concurrent use.
+type SeenStore interface {
+	// Has reports whether digest was added.
+	Has(digest string) (bool, error)
+	// Add records digest. It is called after the document with that digest
+	// was parsed successfully.
+	Add(digest string) error
+}
+
+// WithDeduplication makes a Batch compute the digest of every document
+// before sending it, and skip documents with the same content as one already
+// processed in the same run, reporting them with BatchResult.Duplicate. Each
+// document is read into memory to compute its digest.
+func WithDeduplication() BatchOption {
+	return func(b *Batch) {
+		b.dedup = true
+	}
+}
+
+// WithSeenStore is like WithDeduplication, but also skips documents found in
+// s, and adds every document parsed successfully to s. Use a persistent s to
+// skip documents processed in earlier runs.
+func WithSeenStore(s SeenStore) BatchOption {
+	return func(b *Batch) {
+		b.dedup = true
+		b.seen = s
+	}
+}
+
+// MemorySeenStore is a SeenStore that keeps digests in memory.
+type MemorySeenStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewMemorySeenStore creates an empty MemorySeenStore.
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: make(map[string]bool)}
+}
+
+// Has implements SeenStore.
+func (s *MemorySeenStore) Has(digest string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[digest], nil
+}
+
+// Add implements SeenStore.
+func (s *MemorySeenStore) Add(digest string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[digest] = true
+	return nil
+}
+
+// A deduplicator skips documents already processed during one Run.
+type deduplicator struct {
+	seen SeenStore
+
+	mu       sync.Mutex
+	inFlight map[string]bool // Digests being parsed.
+}
+
+// newDeduplicator returns a deduplicator for a Run of b, or nil if b doesn't
+// deduplicate documents.
+func (b *Batch) newDeduplicator() *deduplicator {
+	if !b.dedup {
+		return nil
+	}
+	seen := b.seen
+	if seen == nil {
+		seen = NewMemorySeenStore()
+	}
+	return &deduplicator{seen: seen, inFlight: make(map[string]bool)}
+}
+
+// parse computes the digest of r and, unless it is a duplicate, calls send
+// to parse it. A document is a duplicate if it is in d.seen or a document with
+// the same digest is being parsed.
+func (d *deduplicator) parse(res BatchResult, r io.Reader, send func(io.Reader, BatchResult) BatchResult) BatchResult {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	sum := sha256.Sum256(body)
+	res.Digest = hex.EncodeToString(sum[:])
+
+	d.mu.Lock()
+	dup := d.inFlight[res.Digest]
+	if !dup {
+		if dup, err = d.seen.Has(res.Digest); err == nil && !dup {
+			d.inFlight[res.Digest] = true
+		}
+	}
+	d.mu.Unlock()
+	if err != nil || dup {
+		res.Duplicate = dup
+		res.Err = err
+		return res
+	}
+
+	res = send(bytes.NewReader(body), res)
+	if res.Err == nil {
+		res.Err = d.seen.Add(res.Digest)
+	}
+	d.mu.Lock()
+	delete(d.inFlight, res.Digest)
+	d.mu.Unlock()
+	return res
+}
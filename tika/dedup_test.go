@@ -0,0 +1,110 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithDeduplication(t *testing.T) {
+	var mu sync.Mutex
+	parsed := make(map[string]int)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		parsed[string(body)]++
+		mu.Unlock()
+		if string(body) == "bad" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.Write(body)
+	}))
+	defer ts.Close()
+	seen := NewMemorySeenStore()
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)}, WithSeenStore(seen))
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+	run := func(docs ...string) (duplicates int) {
+		inputs := make(chan BatchInput)
+		go func() {
+			defer close(inputs)
+			for _, d := range docs {
+				inputs <- BatchInput{ID: d, Reader: strings.NewReader(d)}
+			}
+		}()
+		for res := range b.Run(context.Background(), inputs) {
+			if res.Digest == "" {
+				t.Errorf("%s got no digest", res.ID)
+			}
+			if res.Duplicate {
+				duplicates++
+			} else if res.Err == nil && res.Text != res.ID {
+				t.Errorf("%s = %q, want %q", res.ID, res.Text, res.ID)
+			}
+		}
+		return duplicates
+	}
+
+	if n := run("a", "b", "a", "bad"); n != 1 {
+		t.Errorf("first run got %d duplicates, want 1", n)
+	}
+	// a and b are in the store, but bad failed so it is parsed again.
+	if n := run("a", "b", "bad", "c"); n != 2 {
+		t.Errorf("second run got %d duplicates, want 2", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for doc, want := range map[string]int{"a": 1, "b": 1, "bad": 2, "c": 1} {
+		if parsed[doc] != want {
+			t.Errorf("%s parsed %d times, want %d", doc, parsed[doc], want)
+		}
+	}
+}
+
+func TestWithDeduplicationPerRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("text"))
+	}))
+	defer ts.Close()
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)}, WithDeduplication())
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		inputs := make(chan BatchInput, 2)
+		inputs <- BatchInput{ID: "1", Reader: strings.NewReader("same")}
+		inputs <- BatchInput{ID: "2", Reader: strings.NewReader("same")}
+		close(inputs)
+		duplicates := 0
+		for res := range b.Run(context.Background(), inputs) {
+			if res.Duplicate {
+				duplicates++
+			}
+		}
+		if duplicates != 1 {
+			t.Errorf("run %d got %d duplicates, want 1", i, duplicates)
+		}
+	}
+}
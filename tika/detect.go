@@ -0,0 +1,96 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// A DetectSource reports what detected a MIME type. See DetectReader.
+type DetectSource string
+
+// Sources of a MIME type returned by DetectReader.
+const (
+	// DetectedByServer means the Tika Server detected the type.
+	DetectedByServer DetectSource = "server"
+	// DetectedByExtension means the type was looked up from the file name
+	// extension because the server failed.
+	DetectedByExtension DetectSource = "extension"
+	// DetectedByContent means the type was sniffed from the first bytes of
+	// the input with http.DetectContentType because the server failed.
+	DetectedByContent DetectSource = "content"
+)
+
+// sniffLen is the number of bytes http.DetectContentType considers.
+const sniffLen = 512
+
+// extensionTypes are the types of common document extensions, which are
+// often missing from the system MIME type tables used by mime.TypeByExtension.
+var extensionTypes = map[string]string{
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".eml":  "message/rfc822",
+	".epub": "application/epub+zip",
+	".msg":  "application/vnd.ms-outlook",
+	".odp":  "application/vnd.oasis.opendocument.presentation",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".pdf":  "application/pdf",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".rtf":  "application/rtf",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// DetectReader is like Detect, but falls back to detecting the type locally
+// if the Tika Server fails, so callers can keep working during an outage.
+// filename is optional: it is sent to the server as WithFilename, and used to
+// look up the type by its extension. The source reports what detected the
+// type. An error is only returned if the input can't be read or ctx is done.
+func (c *Client) DetectReader(ctx context.Context, input io.Reader, filename string, opts ...RequestOption) (mimeType string, source DetectSource, err error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(input, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", "", err
+	}
+	head = head[:n]
+	if filename != "" {
+		opts = append([]RequestOption{WithFilename(filename)}, opts...)
+	}
+	mimeType, err = c.Detect(ctx, io.MultiReader(bytes.NewReader(head), input), opts...)
+	if err == nil {
+		return mimeType, DetectedByServer, nil
+	}
+	if ctx.Err() != nil {
+		return "", "", ctx.Err()
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	if t, ok := extensionTypes[ext]; ok {
+		return t, DetectedByExtension, nil
+	}
+	if t := mime.TypeByExtension(ext); ext != "" && t != "" {
+		return t, DetectedByExtension, nil
+	}
+	return http.DetectContentType(head), DetectedByContent, nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDetectReader(t *testing.T) {
+	input := "%PDF-1.4 " + strings.Repeat("x", 1000)
+	var gotBody, gotDisposition string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody, gotDisposition = string(b), r.Header.Get("Content-Disposition")
+		fmt.Fprint(w, "application/x-test")
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	mimeType, source, err := c.DetectReader(context.Background(), strings.NewReader(input), "a.pdf")
+	if err != nil {
+		t.Fatalf("DetectReader got error: %v", err)
+	}
+	if mimeType != "application/x-test" || source != DetectedByServer {
+		t.Errorf("DetectReader got (%q, %q), want (%q, %q)", mimeType, source, "application/x-test", DetectedByServer)
+	}
+	if gotBody != input {
+		t.Errorf("DetectReader sent %d bytes, want the %d bytes of the input", len(gotBody), len(input))
+	}
+	if want := "attachment; filename=a.pdf"; gotDisposition != want {
+		t.Errorf("DetectReader sent Content-Disposition %q, want %q", gotDisposition, want)
+	}
+}
+
+func TestDetectReaderFallback(t *testing.T) {
+	tests := []struct {
+		input, filename string
+		want            string
+		wantSource      DetectSource
+	}{
+		{"PK\x03\x04", "report.DOCX", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", DetectedByExtension},
+		{"%PDF-1.4", "", "application/pdf", DetectedByContent},
+		{"%PDF-1.4", "no_extension", "application/pdf", DetectedByContent},
+		{"", "", "text/plain; charset=utf-8", DetectedByContent},
+	}
+	for _, test := range tests {
+		mimeType, source, err := errorClient.DetectReader(context.Background(), strings.NewReader(test.input), test.filename)
+		if err != nil {
+			t.Errorf("DetectReader(%q, %q) got error: %v", test.input, test.filename, err)
+			continue
+		}
+		if mimeType != test.want || source != test.wantSource {
+			t.Errorf("DetectReader(%q, %q) got (%q, %q), want (%q, %q)", test.input, test.filename, mimeType, source, test.want, test.wantSource)
+		}
+	}
+}
+
+func TestDetectReaderCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := errorClient.DetectReader(ctx, strings.NewReader("test"), "a.pdf"); err != context.Canceled {
+		t.Errorf("DetectReader with a canceled context got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// A Runner runs a Tika Server that Clients can connect to at URL. Server and
+// DockerServer are Runners.
+type Runner interface {
+	Start(ctx context.Context) error
+	Stop() error
+	URL() string
+}
+
+var (
+	_ Runner = (*Server)(nil)
+	_ Runner = (*DockerServer)(nil)
+)
+
+// DockerServer represents a Tika Server running in a Docker container,
+// started from the official apache/tika image. Create a new DockerServer with
+// NewDockerServer, start it with Start, and remove the container with Stop.
+// The docker binary must be in the PATH.
+type DockerServer struct {
+	image string
+	port  string
+	url   string
+	id    string // id is the container ID, set by Start.
+}
+
+// DefaultDockerImage is the image used by NewDockerServer if no image is
+// specified.
+const DefaultDockerImage = "apache/tika:latest"
+
+// NewDockerServer creates a new DockerServer running image, for example
+// "apache/tika:1.21". The container's port 9998 is published on port of the
+// host. The default image is DefaultDockerImage and the default port is 9998.
+func NewDockerServer(image, port string) (*DockerServer, error) {
+	if image == "" {
+		image = DefaultDockerImage
+	}
+	if port == "" {
+		port = "9998"
+	}
+	u, err := url.Parse("http://localhost:" + port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q: %v", port, err)
+	}
+	return &DockerServer{image: image, port: port, url: u.String()}, nil
+}
+
+// URL returns the URL of this DockerServer.
+func (d *DockerServer) URL() string {
+	return d.url
+}
+
+// docker runs docker with args and returns its stdout.
+func docker(ctx context.Context, args ...string) (string, error) {
+	cmd := command("docker", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return "", fmt.Errorf("docker %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		return "", ctx.Err()
+	}
+}
+
+// Start starts a new container in the background and waits for the server
+// in it to be available or until ctx is cancelled. The caller must call Stop
+// to remove the container when finished with the DockerServer. If the server
+// does not start, the container logs are included in the error and the
+// container is removed.
+func (d *DockerServer) Start(ctx context.Context) error {
+	id, err := docker(ctx, "run", "--detach", "--rm", "--publish", d.port+":9998", d.image)
+	if err != nil {
+		return fmt.Errorf("could not start container: %v", err)
+	}
+	d.id = id
+
+	if err := waitForServer(ctx, NewClient(nil, d.url), defaultPollInterval, nil); err != nil {
+		// Use a fresh context since ctx may be done.
+		logs, logErr := docker(context.Background(), "logs", id)
+		if logErr != nil {
+			logs = fmt.Sprintf("error reading logs: %v", logErr)
+		}
+		docker(context.Background(), "rm", "--force", id)
+		d.id = ""
+		return fmt.Errorf("error starting server: %v\ncontainer logs:\n\n%s", err, logs)
+	}
+	return nil
+}
+
+// Stop stops the container, which removes it. If d has not been started,
+// Stop returns an error.
+func (d *DockerServer) Stop() error {
+	if d.id == "" {
+		return fmt.Errorf("container not started")
+	}
+	if _, err := docker(context.Background(), "stop", d.id); err != nil {
+		return fmt.Errorf("could not stop container: %v", err)
+	}
+	d.id = ""
+	return nil
+}
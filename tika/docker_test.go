@@ -0,0 +1,114 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeDocker replaces command with a fake docker binary that prints out for
+// every invocation. It returns a pointer to the recorded arguments.
+func fakeDocker(t *testing.T, out string) *[][]string {
+	t.Helper()
+	old := command
+	t.Cleanup(func() { command = old })
+	var calls [][]string
+	command = func(name string, args ...string) *exec.Cmd {
+		calls = append(calls, append([]string{name}, args...))
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "print", out)
+		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		return c
+	}
+	return &calls
+}
+
+func TestNewDockerServer(t *testing.T) {
+	d, err := NewDockerServer("", "")
+	if err != nil {
+		t.Fatalf("NewDockerServer got error: %v", err)
+	}
+	if d.image != DefaultDockerImage {
+		t.Errorf("NewDockerServer image = %q, want %q", d.image, DefaultDockerImage)
+	}
+	if got, want := d.URL(), "http://localhost:9998"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+	if _, err := NewDockerServer("", "%31"); err == nil {
+		t.Errorf("NewDockerServer with invalid port got no error, want an error")
+	}
+}
+
+func TestDockerServer(t *testing.T) {
+	calls := fakeDocker(t, "container-id")
+	ts := bouncyServer(1)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	d, err := NewDockerServer("apache/tika:1.21", tsURL.Port())
+	if err != nil {
+		t.Fatalf("NewDockerServer got error: %v", err)
+	}
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	if err := d.Stop(); err != nil {
+		t.Fatalf("Stop got error: %v", err)
+	}
+	want := [][]string{
+		{"docker", "run", "--detach", "--rm", "--publish", tsURL.Port() + ":9998", "apache/tika:1.21"},
+		{"docker", "stop", "container-id"},
+	}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("docker calls = %q, want %q", *calls, want)
+	}
+	if err := d.Stop(); err == nil {
+		t.Errorf("second Stop got no error, want an error")
+	}
+}
+
+func TestDockerServerStartError(t *testing.T) {
+	calls := fakeDocker(t, "container-id")
+	ts := bouncyServer(100)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	d, err := NewDockerServer("", tsURL.Port())
+	if err != nil {
+		t.Fatalf("NewDockerServer got error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := d.Start(ctx); err == nil {
+		t.Fatalf("Start got no error, want an error")
+	}
+	if got := len(*calls); got != 3 {
+		t.Fatalf("got %d docker calls, want run, logs and rm: %q", got, *calls)
+	}
+	if got, want := (*calls)[2], []string{"docker", "rm", "--force", "container-id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("cleanup call = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,712 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	// x/crypto/openpgp is deprecated and frozen, but it is only used to check
+	// detached RSA signatures against keys supplied by the caller, which it
+	// still does correctly, and it keeps the dependencies within golang.org/x.
+	// Apache release keys are RSA, so its lack of newer algorithms such as
+	// Ed25519 doesn't matter here.
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+func sha512Hash(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha512.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// A Version represents a Tika Server version.
+type Version string
+
+// Supported versions of Tika Server.
+const (
+	Version119  Version = "1.19"
+	Version120  Version = "1.20"
+	Version121  Version = "1.21"
+	Version122  Version = "1.22"
+	Version123  Version = "1.23"
+	Version124  Version = "1.24"
+	Version1241 Version = "1.24.1"
+	Version125  Version = "1.25"
+	Version126  Version = "1.26"
+	Version127  Version = "1.27"
+	Version128  Version = "1.28"
+	Version1281 Version = "1.28.1"
+	Version1282 Version = "1.28.2"
+	Version1283 Version = "1.28.3"
+	Version1284 Version = "1.28.4"
+	Version1285 Version = "1.28.5"
+	Version200  Version = "2.0.0"
+	Version210  Version = "2.1.0"
+	Version220  Version = "2.2.0"
+	Version221  Version = "2.2.1"
+	Version230  Version = "2.3.0"
+	Version240  Version = "2.4.0"
+	Version241  Version = "2.4.1"
+	Version250  Version = "2.5.0"
+	Version260  Version = "2.6.0"
+	Version270  Version = "2.7.0"
+	Version280  Version = "2.8.0"
+	Version290  Version = "2.9.0"
+	Version291  Version = "2.9.1"
+	Version292  Version = "2.9.2"
+)
+
+// A release is a supported version of Tika Server.
+type release struct {
+	version Version
+	// sha512 is the SHA-512 of the server JAR, as published by Apache. If it
+	// is empty, the release is not listed in Versions and is only downloaded
+	// with WithPublishedChecksum, like a version that isn't listed.
+	sha512 string
+}
+
+// releases lists the supported versions of Tika Server, oldest first. To
+// support a new release, add a Version constant and an entry here.
+var releases = []release{
+	{Version119, "a9e2b6186cdb9872466d3eda791d0e1cd059da923035940d4b51bb1adc4a356670fde46995725844a2dd500a09f3a5631d0ca5fbc2d61a59e8e0bd95c9dfa6c2"},
+	{Version120, "a7ef35317aba76be8606f9250893efece8b93384e835a18399da18a095b19a15af591e3997828d4ebd3023f21d5efad62a91918610c44e692cfd9bed01d68382"},
+	{Version121, "e705c836b2110530c8d363d05da27f65c4f6c9051b660cefdae0e5113c365dbabed2aa1e4171c8e52dbe4cbaa085e3d8a01a5a731e344942c519b85836da646c"},
+	{Version122, ""},
+	{Version123, ""},
+	{Version124, ""},
+	{Version1241, ""},
+	{Version125, ""},
+	{Version126, ""},
+	{Version127, ""},
+	{Version128, ""},
+	{Version1281, ""},
+	{Version1282, ""},
+	{Version1283, ""},
+	{Version1284, ""},
+	{Version1285, ""},
+	{Version200, ""},
+	{Version210, ""},
+	{Version220, ""},
+	{Version221, ""},
+	{Version230, ""},
+	{Version240, ""},
+	{Version241, ""},
+	{Version250, ""},
+	{Version260, ""},
+	{Version270, ""},
+	{Version280, ""},
+	{Version290, ""},
+	{Version291, ""},
+	{Version292, ""},
+}
+
+// Versions is a list of supported versions of Apache Tika, oldest first.
+// Only releases with a SHA-512 built into this package are listed.
+var Versions = func() []Version {
+	var vs []Version
+	for _, r := range releases {
+		if r.sha512 != "" {
+			vs = append(vs, r.version)
+		}
+	}
+	return vs
+}()
+
+// lookupRelease returns the release of v.
+func lookupRelease(v Version) (release, bool) {
+	for _, r := range releases {
+		if r.version == v {
+			return r, true
+		}
+	}
+	return release{}, false
+}
+
+// mavenURL is the root of Maven Central. It is followed by the path of the
+// artifact in the repository.
+var mavenURL = "https://repo1.maven.org/maven2/"
+
+// serverArtifact returns the Maven artifact ID of the server JAR for the given
+// major version. Tika 2.0 renamed tika-server to tika-server-standard.
+func serverArtifact(major int) string {
+	if major == 1 {
+		return "tika-server"
+	}
+	return "tika-server-standard"
+}
+
+// ServerJARName returns the file name of the server JAR of v, as published on
+// Maven, for example tika-server-1.21.jar or tika-server-standard-2.9.2.jar.
+func ServerJARName(v Version) string {
+	return fmt.Sprintf("%s-%s.jar", serverArtifact(v.major()), v)
+}
+
+// serverJARPath returns the path of the server JAR of v in a Maven
+// repository.
+func serverJARPath(v Version) string {
+	return fmt.Sprintf("org/apache/tika/%s/%s/%s", serverArtifact(v.major()), v, ServerJARName(v))
+}
+
+// major returns the major version of v, or 0 if v is not a valid version.
+func (v Version) major() int {
+	n, err := strconv.Atoi(strings.SplitN(string(v), ".", 2)[0])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// fetchSHA512 downloads the SHA-512 checksum file at url. The file contains
+// the hex encoded checksum, optionally followed by the file name.
+func fetchSHA512(ctx context.Context, client *http.Client, url string) (string, error) {
+	resp, err := ctxhttp.Get(ctx, client, url)
+	if err != nil {
+		return "", fmt.Errorf("unable to download %q: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to download %q: response code %v", url, resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", fmt.Errorf("unable to download %q: %v", url, err)
+	}
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 || len(fields[0]) != 2*sha512.Size {
+		return "", fmt.Errorf("invalid sha512 file %q", url)
+	}
+	if _, err := hex.DecodeString(fields[0]); err != nil {
+		return "", fmt.Errorf("invalid sha512 file %q: %v", url, err)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// A DownloadOption configures DownloadServer.
+type DownloadOption func(*downloadConfig)
+
+type downloadConfig struct {
+	publishedChecksum bool
+	progress          func(downloaded, total int64)
+	mirrors           []string
+	client            *http.Client
+	keyring           openpgp.EntityList
+	keyringErr        error
+	logger            *slog.Logger
+}
+
+// maxRedirects is the number of redirects followed when downloading, the same
+// limit as http.Client's default policy.
+const maxRedirects = 10
+
+// secureClient returns a copy of client, or http.DefaultClient if client is
+// nil, that follows redirects like mirrors and repository managers send, but
+// refuses to be redirected from HTTPS to plain HTTP.
+func secureClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := *client
+	check := client.CheckRedirect
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if prev := via[len(via)-1]; prev.URL.Scheme == "https" && req.URL.Scheme != "https" {
+			return fmt.Errorf("refusing insecure redirect from %s to %s", prev.URL, req.URL)
+		}
+		if check != nil {
+			return check(req, via)
+		}
+		return nil
+	}
+	return &c
+}
+
+// repositories returns the prefixes of the Maven repositories to download
+// from, in order. Each prefix is followed by the path of an artifact.
+func (c *downloadConfig) repositories() []string {
+	if len(c.mirrors) == 0 {
+		return []string{mavenURL}
+	}
+	var repos []string
+	for _, m := range c.mirrors {
+		repos = append(repos, strings.TrimSuffix(m, "/")+"/")
+	}
+	return repos
+}
+
+// jarURLs returns the URLs to try to download the server JAR of v from, in
+// order.
+func (c *downloadConfig) jarURLs(v Version) []string {
+	var urls []string
+	for _, r := range c.repositories() {
+		urls = append(urls, r+serverJARPath(v))
+	}
+	return urls
+}
+
+// WithPublishedChecksum allows downloading versions without a checksum built
+// into this package, such as versions that are not listed in Versions, by
+// verifying the JAR against the SHA-512 checksum published next to it on
+// Maven. The checksum comes from the same server as the JAR, so it only
+// detects corrupted downloads, not tampering. Versions with a built-in
+// checksum are always verified against it.
+func WithPublishedChecksum() DownloadOption {
+	return func(c *downloadConfig) {
+		c.publishedChecksum = true
+	}
+}
+
+// WithProgress calls f as the JAR is downloaded with the number of bytes
+// downloaded so far and the total size of the JAR, or -1 if the size is not
+// known. When a download is resumed, downloaded starts at the size of the
+// partial download.
+func WithProgress(f func(downloaded, total int64)) DownloadOption {
+	return func(c *downloadConfig) {
+		c.progress = f
+	}
+}
+
+// WithMirrors downloads the JAR from the given Maven repositories instead of
+// Maven Central, for example an internal Artifactory or Nexus mirror. Each
+// URL is the root of a repository using the standard Maven layout, like
+// https://repo1.maven.org/maven2. The mirrors are tried in order until one
+// succeeds.
+func WithMirrors(urls ...string) DownloadOption {
+	return func(c *downloadConfig) {
+		c.mirrors = append(c.mirrors, urls...)
+	}
+}
+
+// WithDownloadClient downloads the JAR using client, for example to set a
+// proxy, timeout or custom root CAs. By default, http.DefaultClient is used,
+// which respects the HTTP_PROXY and HTTPS_PROXY environment variables.
+// Whichever client is used, redirects from HTTPS to plain HTTP are refused.
+func WithDownloadClient(client *http.Client) DownloadOption {
+	return func(c *downloadConfig) {
+		c.client = client
+	}
+}
+
+// TikaKEYSURL is the URL of the KEYS file listing the public keys used to sign
+// Apache Tika releases. See WithSignatureVerification.
+const TikaKEYSURL = "https://downloads.apache.org/tika/KEYS"
+
+// WithSignatureVerification verifies the detached PGP signature (.asc) of the
+// downloaded JAR against the public keys in keys, in addition to the
+// checksum. keys is an Apache KEYS file: any number of ASCII armored public
+// key blocks, optionally surrounded by text. For the check to prove where the
+// JAR came from, keys must be obtained from a trusted source, for example a
+// copy of TikaKEYSURL that is checked in alongside your code.
+func WithSignatureVerification(keys io.Reader) DownloadOption {
+	return func(c *downloadConfig) {
+		c.keyring, c.keyringErr = readKEYS(keys)
+	}
+}
+
+// readKEYS reads every armored public key block in r.
+func readKEYS(r io.Reader) (openpgp.EntityList, error) {
+	br := bufio.NewReader(r)
+	var keyring openpgp.EntityList
+	for {
+		block, err := armor.Decode(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid KEYS file: %v", err)
+		}
+		if block.Type != openpgp.PublicKeyType {
+			continue
+		}
+		keys, err := openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid KEYS file: %v", err)
+		}
+		keyring = append(keyring, keys...)
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("invalid KEYS file: no public keys found")
+	}
+	return keyring, nil
+}
+
+// verifySignature verifies the file at path against the armored detached
+// signature at sigURL using keyring.
+func verifySignature(ctx context.Context, client *http.Client, keyring openpgp.KeyRing, path, sigURL string) error {
+	resp, err := ctxhttp.Get(ctx, client, sigURL)
+	if err != nil {
+		return fmt.Errorf("unable to download %q: %v", sigURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download %q: response code %v", sigURL, resp.StatusCode)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := openpgp.CheckArmoredDetachedSignature(keyring, f, resp.Body); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	return nil
+}
+
+// DownloadServer downloads and validates the given server version,
+// saving it at path. DownloadServer returns an error if it could
+// not be downloaded/validated.
+// It is the caller's responsibility to remove the file when no longer needed.
+// If the file already exists and has the correct sha512, DownloadServer will
+// do nothing.
+// By default, the JAR is downloaded over HTTPS from Maven Central and checked
+// against the sha512 built into this package. Versions without one are only
+// downloaded with WithPublishedChecksum.
+// The JAR is downloaded to path + ".part" and renamed once it is validated. If
+// a download is interrupted, calling DownloadServer again resumes it.
+func DownloadServer(ctx context.Context, v Version, path string, opts ...DownloadOption) error {
+	cfg := &downloadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.client = secureClient(cfg.client)
+	if cfg.keyringErr != nil {
+		return cfg.keyringErr
+	}
+	r, ok := lookupRelease(v)
+	if !ok && !cfg.publishedChecksum {
+		return fmt.Errorf("unsupported Tika version: %s", v)
+	}
+	if r.sha512 == "" && !cfg.publishedChecksum {
+		return fmt.Errorf("no built-in sha512 for Tika version %s: use WithPublishedChecksum to verify the published checksum", v)
+	}
+	var errs []string
+	for _, url := range cfg.jarURLs(v) {
+		logAttrs(ctx, cfg.logger, slog.LevelInfo, "downloading tika server", slog.String("url", url), slog.String("path", path))
+		err := downloadServerFrom(ctx, cfg, url, r.sha512, path)
+		if err == nil {
+			logAttrs(ctx, cfg.logger, slog.LevelInfo, "downloaded tika server", slog.String("url", url), slog.String("path", path))
+			return nil
+		}
+		logAttrs(ctx, cfg.logger, slog.LevelWarn, "tika server download failed", slog.String("url", url), slog.Any("error", err))
+		errs = append(errs, err.Error())
+	}
+	return errors.New(strings.Join(errs, "; "))
+}
+
+// downloadServerFrom downloads the JAR at url to path, verifying it against
+// hash, or the published checksum if hash is empty.
+func downloadServerFrom(ctx context.Context, cfg *downloadConfig, url, hash, path string) error {
+	if hash == "" {
+		var err error
+		if hash, err = fetchSHA512(ctx, cfg.client, url+".sha512"); err != nil {
+			return err
+		}
+	}
+	if got, err := sha512Hash(path); err == nil && got == hash {
+		if cfg.keyring == nil {
+			return nil
+		}
+		// Don't trust a previous download without a signature check.
+		if err := verifySignature(ctx, cfg.client, cfg.keyring, path, url+".asc"); err == nil {
+			return nil
+		}
+	}
+	// Download to a separate file so an interrupted download can be resumed
+	// and path never contains a partial JAR.
+	part := path + ".part"
+	if err := download(ctx, cfg.client, url, part, logProgress(cfg.logger, url, cfg.progress)); err != nil {
+		return err
+	}
+
+	h, err := sha512Hash(part)
+
+	if err != nil {
+		return err
+	}
+	if h != hash {
+		if err := os.Remove(part); err != nil {
+			return fmt.Errorf("invalid sha512: %s: error removing %s: %v", h, part, err)
+		}
+		return fmt.Errorf("invalid sha512: %s", h)
+	}
+	if cfg.keyring != nil {
+		if err := verifySignature(ctx, cfg.client, cfg.keyring, part, url+".asc"); err != nil {
+			if rmErr := os.Remove(part); rmErr != nil {
+				return fmt.Errorf("%v: error removing %s: %v", err, part, rmErr)
+			}
+			return err
+		}
+	}
+	return os.Rename(part, path)
+}
+
+// download downloads url to path. If path already exists, download asks the
+// server for the rest of the file with a Range request, and appends to path.
+// If the server doesn't support ranges, path is overwritten.
+func download(ctx context.Context, client *http.Client, url, path string, progress func(downloaded, total int64)) error {
+	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+	defer out.Close()
+	offset, err := out.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("error creating file: %v", err)
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := ctxhttp.Do(ctx, client, req)
+	if err != nil {
+		return fmt.Errorf("unable to download %q: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The previous download got the whole file, let the caller verify it.
+		return nil
+	case http.StatusOK:
+		if err := out.Truncate(0); err != nil {
+			return fmt.Errorf("error saving download: %v", err)
+		}
+		if _, err := out.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("error saving download: %v", err)
+		}
+		offset = 0
+	default:
+		return fmt.Errorf("unable to download %q: response code %v", url, resp.StatusCode)
+	}
+
+	var w io.Writer = out
+	if progress != nil {
+		total := int64(-1)
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+		progress(offset, total)
+		w = &progressWriter{w: out, n: offset, total: total, f: progress}
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("error saving download: %v", err)
+	}
+	return out.Close()
+}
+
+// progressWriter is an io.Writer that calls f with the number of bytes
+// written so far after every write.
+type progressWriter struct {
+	w        io.Writer
+	n, total int64
+	f        func(n, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.n += int64(n)
+	p.f(p.n, p.total)
+	return n, err
+}
+
+// mavenMetadata is the maven-metadata.xml of an artifact.
+type mavenMetadata struct {
+	Versions []string `xml:"versioning>versions>version"`
+}
+
+// LatestVersion returns the newest release of Tika Server published on Maven.
+// If major is greater than zero, LatestVersion returns the newest release of
+// that major version. Otherwise, only releases since 2.0 (published as
+// tika-server-standard) are considered. Pre-releases, like 2.0.0-BETA, are
+// ignored. The
+// returned Version may not be listed in Versions; pass WithPublishedChecksum
+// to DownloadServer to download it.
+func LatestVersion(ctx context.Context, major int, opts ...DownloadOption) (Version, error) {
+	cfg := &downloadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.client = secureClient(cfg.client)
+	var errs []string
+	for _, r := range cfg.repositories() {
+		url := fmt.Sprintf("%sorg/apache/tika/%s/maven-metadata.xml", r, serverArtifact(major))
+		v, err := latestVersionFrom(ctx, cfg.client, url, major)
+		if err == nil {
+			return v, nil
+		}
+		errs = append(errs, err.Error())
+	}
+	return "", errors.New(strings.Join(errs, "; "))
+}
+
+// latestVersionFrom returns the newest version listed in the
+// maven-metadata.xml at url with the given major version.
+func latestVersionFrom(ctx context.Context, client *http.Client, url string, major int) (Version, error) {
+	resp, err := ctxhttp.Get(ctx, client, url)
+	if err != nil {
+		return "", fmt.Errorf("unable to download %q: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to download %q: response code %v", url, resp.StatusCode)
+	}
+	var m mavenMetadata
+	if err := xml.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return "", fmt.Errorf("invalid maven metadata %q: %v", url, err)
+	}
+	var latest []int
+	var latestVersion string
+	for _, v := range m.Versions {
+		nums, ok := versionNumbers(v)
+		if !ok || (major > 0 && nums[0] != major) {
+			continue
+		}
+		if latest == nil || compareVersionNumbers(nums, latest) > 0 {
+			latest, latestVersion = nums, v
+		}
+	}
+	if latest == nil {
+		if major > 0 {
+			return "", fmt.Errorf("no %d.x release found in %q", major, url)
+		}
+		return "", fmt.Errorf("no release found in %q", url)
+	}
+	return Version(latestVersion), nil
+}
+
+// versionNumbers splits a version like "1.24.1" into its numbers. ok is false
+// if v is not a dotted list of numbers.
+func versionNumbers(v string) (nums []int, ok bool) {
+	for _, part := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		nums = append(nums, n)
+	}
+	return nums, true
+}
+
+// compareVersionNumbers returns -1, 0 or 1 if a is older than, the same as or
+// newer than b. Missing numbers are treated as zero, so 1.24 equals 1.24.0.
+func compareVersionNumbers(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// DownloadLatest downloads the newest release of Tika Server, as reported by
+// LatestVersion, to path and returns its version. The JAR is verified against
+// the published checksum if the release is not listed in Versions. See
+// DownloadServer.
+func DownloadLatest(ctx context.Context, major int, path string, opts ...DownloadOption) (Version, error) {
+	v, err := LatestVersion(ctx, major, opts...)
+	if err != nil {
+		return "", err
+	}
+	if r, _ := lookupRelease(v); r.sha512 == "" {
+		opts = append(opts, WithPublishedChecksum())
+	}
+	if err := DownloadServer(ctx, v, path, opts...); err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+// CacheDir returns the directory CachedServerJAR stores server JARs in: a
+// go-tika directory in the user's cache directory (see os.UserCacheDir), for
+// example ~/.cache/go-tika on Linux.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-tika"), nil
+}
+
+// CachedServerJAR returns the path of the server JAR of v in CacheDir,
+// downloading it with DownloadServer if it is missing or invalid.
+func CachedServerJAR(ctx context.Context, v Version, opts ...DownloadOption) (string, error) {
+	dir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating cache directory: %v", err)
+	}
+	path := filepath.Join(dir, ServerJARName(v))
+	if err := DownloadServer(ctx, v, path, opts...); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// EnsureServer makes sure the server JAR of v is in CacheDir, downloading it
+// if needed, and returns a new Server using it on the given port. The Server
+// is not started. opts are passed to NewServer. v must have a checksum built
+// into this package, like the releases in Versions; for other versions, use
+// CachedServerJAR with WithPublishedChecksum and NewServer.
+func EnsureServer(ctx context.Context, v Version, port string, opts ...ServerOption) (*Server, error) {
+	jar, err := CachedServerJAR(ctx, v)
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(jar, port, append([]ServerOption{WithTikaVersion(v)}, opts...)...)
+}
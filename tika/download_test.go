@@ -0,0 +1,593 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha512"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+)
+
+func TestValidateFileHash(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"path_to_non_existent_file", true},
+		{path, false},
+	}
+	for _, test := range tests {
+		_, err := sha512Hash(test.path)
+		if test.wantErr && err == nil {
+			t.Errorf("getHash(%s) wanted an error", test.path)
+			continue
+		}
+		if !test.wantErr && err != nil {
+			t.Errorf("getHash(%s) got an error: %v", test.path, err)
+		}
+	}
+}
+
+func TestDownloadServerError(t *testing.T) {
+	tests := []struct {
+		version Version
+		path    string
+	}{
+		{"1.0", ""},
+		// The release is listed, but without a checksum to verify it against.
+		{Version122, ""},
+	}
+	for _, test := range tests {
+		if err := DownloadServer(context.Background(), test.version, test.path); err == nil {
+			t.Errorf("DownloadServer(%q, %q) got no error, want an error", test.version, test.path)
+		}
+	}
+}
+
+// fakeMaven serves jar and its SHA-512 checksum for every requested
+// artifact. It replaces mavenURL until the returned function is called.
+func fakeMaven(t *testing.T, jar []byte) func() {
+	t.Helper()
+	sum := sha512.Sum512(jar)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/maven2/")
+		switch {
+		case strings.HasSuffix(path, ".jar"):
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(jar))
+		case strings.HasSuffix(path, ".jar.sha512"):
+			fmt.Fprintf(w, "%x\n", sum)
+		case path == "org/apache/tika/tika-server/maven-metadata.xml":
+			fmt.Fprint(w, mavenMetadataXML)
+		case path == "org/apache/tika/tika-server-standard/maven-metadata.xml":
+			fmt.Fprint(w, mavenStandardMetadataXML)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	old := mavenURL
+	mavenURL = ts.URL + "/maven2/"
+	return func() {
+		mavenURL = old
+		ts.Close()
+	}
+}
+
+func TestDownloadServerPublishedChecksum(t *testing.T) {
+	jar := []byte("not really a jar")
+	defer fakeMaven(t, jar)()
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tika-server.jar")
+
+	if err := DownloadServer(context.Background(), "9.9", path); err == nil {
+		t.Errorf("DownloadServer(9.9) got no error for an unlisted version, want an error")
+	}
+	if err := DownloadServer(context.Background(), "9.9", path, WithPublishedChecksum()); err != nil {
+		t.Fatalf("DownloadServer(9.9, WithPublishedChecksum) got error: %v", err)
+	}
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading download: %v", err)
+	}
+	if string(got) != string(jar) {
+		t.Errorf("DownloadServer saved %q, want %q", got, jar)
+	}
+	// A pinned checksum that doesn't match the download is rejected, even
+	// with WithPublishedChecksum, and the existing file is left alone.
+	if err := DownloadServer(context.Background(), Version121, path, WithPublishedChecksum()); err == nil {
+		t.Errorf("DownloadServer(%s) got no error for a mismatched checksum, want an error", Version121)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Errorf("DownloadServer did not remove the invalid download: %v", err)
+	}
+	if got, err := ioutil.ReadFile(path); err != nil || string(got) != string(jar) {
+		t.Errorf("DownloadServer changed the existing file to %q (%v), want %q", got, err, jar)
+	}
+}
+
+func TestDownloadServerResume(t *testing.T) {
+	jar := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	defer fakeMaven(t, jar)()
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tika-server.jar")
+	if err := ioutil.WriteFile(path+".part", jar[:10], 0644); err != nil {
+		t.Fatalf("error writing partial download: %v", err)
+	}
+
+	var calls [][2]int64
+	progress := func(downloaded, total int64) {
+		calls = append(calls, [2]int64{downloaded, total})
+	}
+	if err := DownloadServer(context.Background(), "9.9", path, WithPublishedChecksum(), WithProgress(progress)); err != nil {
+		t.Fatalf("DownloadServer got error: %v", err)
+	}
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading download: %v", err)
+	}
+	if string(got) != string(jar) {
+		t.Errorf("DownloadServer saved %q, want %q", got, jar)
+	}
+	total := int64(len(jar))
+	if len(calls) < 2 || calls[0] != [2]int64{10, total} || calls[len(calls)-1] != [2]int64{total, total} {
+		t.Errorf("progress calls = %v, want to start at [10 %d] and end at [%d %d]", calls, total, total, total)
+	}
+}
+
+func TestDownloadServerMirrors(t *testing.T) {
+	jar := []byte("not really a jar")
+	sum := sha512.Sum512(jar)
+	var paths []string
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/maven2/org/apache/tika/tika-server/1.99/tika-server-1.99.jar":
+			w.Write(jar)
+		case "/maven2/org/apache/tika/tika-server/1.99/tika-server-1.99.jar.sha512":
+			fmt.Fprintf(w, "%x", sum)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mirror.Close()
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tika-server.jar")
+
+	var used bool
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		used = true
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	err = DownloadServer(context.Background(), "1.99", path,
+		WithPublishedChecksum(),
+		WithMirrors(broken.URL, mirror.URL+"/maven2/"),
+		WithDownloadClient(client))
+	if err != nil {
+		t.Fatalf("DownloadServer got error: %v", err)
+	}
+	if got, err := ioutil.ReadFile(path); err != nil || string(got) != string(jar) {
+		t.Errorf("DownloadServer saved %q (%v), want %q", got, err, jar)
+	}
+	if !used {
+		t.Errorf("DownloadServer did not use the download client")
+	}
+	want := []string{
+		"/maven2/org/apache/tika/tika-server/1.99/tika-server-1.99.jar.sha512",
+		"/maven2/org/apache/tika/tika-server/1.99/tika-server-1.99.jar",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("mirror requests = %q, want %q", paths, want)
+	}
+}
+
+func TestDownloadServerRedirects(t *testing.T) {
+	jar := []byte("not really a jar")
+	sum := sha512.Sum512(jar)
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha512") {
+			fmt.Fprintf(w, "%x", sum)
+			return
+		}
+		w.Write(jar)
+	}))
+	defer plain.Close()
+	redirect := func(target string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, target+r.URL.Path, http.StatusFound)
+		})
+	}
+	secure := httptest.NewTLSServer(redirect(plain.URL))
+	defer secure.Close()
+	insecure := httptest.NewServer(redirect(plain.URL))
+	defer insecure.Close()
+
+	tests := []struct {
+		name    string
+		mirror  string
+		wantErr bool
+	}{
+		{name: "http to http", mirror: insecure.URL},
+		{name: "https to http", mirror: secure.URL, wantErr: true},
+	}
+	for _, test := range tests {
+		path := filepath.Join(t.TempDir(), "tika-server.jar")
+		err := DownloadServer(context.Background(), "1.99", path,
+			WithPublishedChecksum(),
+			WithMirrors(test.mirror),
+			WithDownloadClient(secure.Client()))
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("DownloadServer(%s) got error %v, want error: %v", test.name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("DownloadServer(%s) saved %s after a refused redirect", test.name, path)
+			}
+			continue
+		}
+		if got, err := ioutil.ReadFile(path); err != nil || string(got) != string(jar) {
+			t.Errorf("DownloadServer(%s) saved %q (%v), want %q", test.name, got, err, jar)
+		}
+	}
+}
+
+// roundTripperFunc is an http.RoundTripper implemented by a function.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestServerJARPath(t *testing.T) {
+	tests := []struct {
+		v    Version
+		want string
+	}{
+		{Version121, "org/apache/tika/tika-server/1.21/tika-server-1.21.jar"},
+		{Version1285, "org/apache/tika/tika-server/1.28.5/tika-server-1.28.5.jar"},
+		{Version292, "org/apache/tika/tika-server-standard/2.9.2/tika-server-standard-2.9.2.jar"},
+	}
+	for _, test := range tests {
+		if got := serverJARPath(test.v); got != test.want {
+			t.Errorf("serverJARPath(%s) = %q, want %q", test.v, got, test.want)
+		}
+		if got, want := ServerJARName(test.v), path.Base(test.want); got != want {
+			t.Errorf("ServerJARName(%s) = %q, want %q", test.v, got, want)
+		}
+	}
+}
+
+func TestReleases(t *testing.T) {
+	seen := make(map[Version]bool)
+	var pinned []Version
+	for _, r := range releases {
+		if seen[r.version] {
+			t.Errorf("release %s is listed more than once", r.version)
+		}
+		seen[r.version] = true
+		if r.sha512 != "" && len(r.sha512) != 128 {
+			t.Errorf("release %s has an invalid sha512 %q", r.version, r.sha512)
+		}
+		if r.sha512 != "" {
+			pinned = append(pinned, r.version)
+		}
+	}
+	if !reflect.DeepEqual(Versions, pinned) {
+		t.Errorf("Versions = %v, want the releases with a sha512 %v", Versions, pinned)
+	}
+}
+
+func TestFetchSHA512(t *testing.T) {
+	const sum = "e705c836b2110530c8d363d05da27f65c4f6c9051b660cefdae0e5113c365dbabed2aa1e4171c8e52dbe4cbaa085e3d8a01a5a731e344942c519b85836da646c"
+	tests := []struct {
+		name     string
+		response string
+		status   int
+		wantErr  bool
+	}{
+		{name: "checksum only", response: sum + "\n"},
+		{name: "checksum and file name", response: sum + "  tika-server-1.21.jar\n"},
+		{name: "upper case", response: strings.ToUpper(sum)},
+		{name: "empty", wantErr: true},
+		{name: "too short", response: sum[:64], wantErr: true},
+		{name: "not hex", response: strings.Repeat("z", 128), wantErr: true},
+		{name: "not found", status: http.StatusNotFound, wantErr: true},
+	}
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if test.status != 0 {
+				w.WriteHeader(test.status)
+			}
+			fmt.Fprint(w, test.response)
+		}))
+		got, err := fetchSHA512(context.Background(), nil, ts.URL)
+		ts.Close()
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("fetchSHA512(%s) got no error, want an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("fetchSHA512(%s) got error: %v", test.name, err)
+			continue
+		}
+		if got != sum {
+			t.Errorf("fetchSHA512(%s) = %q, want %q", test.name, got, sum)
+		}
+	}
+}
+
+const mavenMetadataXML = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>org.apache.tika</groupId>
+  <artifactId>tika-server</artifactId>
+  <versioning>
+    <latest>2.0.0-BETA</latest>
+    <release>1.28.5</release>
+    <versions>
+      <version>1.9</version>
+      <version>1.21</version>
+      <version>1.28.5</version>
+      <version>1.28</version>
+      <version>2.0.0-ALPHA</version>
+      <version>2.0.0-BETA</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+const mavenStandardMetadataXML = `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>org.apache.tika</groupId>
+  <artifactId>tika-server-standard</artifactId>
+  <versioning>
+    <versions>
+      <version>2.0.0-BETA</version>
+      <version>2.0.0</version>
+      <version>2.9.2</version>
+      <version>2.10.0-SNAPSHOT</version>
+    </versions>
+  </versioning>
+</metadata>`
+
+func TestLatestVersion(t *testing.T) {
+	defer fakeMaven(t, nil)()
+	tests := []struct {
+		major   int
+		want    Version
+		wantErr bool
+	}{
+		{major: 0, want: Version292},
+		{major: 1, want: Version1285},
+		{major: 2, want: Version292},
+		{major: 3, wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := LatestVersion(context.Background(), test.major)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("LatestVersion(%d) got no error, want an error", test.major)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LatestVersion(%d) got error: %v", test.major, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("LatestVersion(%d) = %s, want %s", test.major, got, test.want)
+		}
+	}
+}
+
+func TestDownloadLatest(t *testing.T) {
+	jar := []byte("not really a jar")
+	defer fakeMaven(t, jar)()
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tika-server.jar")
+	v, err := DownloadLatest(context.Background(), 1, path)
+	if err != nil {
+		t.Fatalf("DownloadLatest got error: %v", err)
+	}
+	if v != Version1285 {
+		t.Errorf("DownloadLatest = %s, want %s", v, Version1285)
+	}
+	if got, err := ioutil.ReadFile(path); err != nil || string(got) != string(jar) {
+		t.Errorf("DownloadLatest saved %q (%v), want %q", got, err, jar)
+	}
+}
+
+func TestCompareVersionNumbers(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.21", "1.21", 0},
+		{"1.24", "1.24.0", 0},
+		{"1.9", "1.21", -1},
+		{"1.24.1", "1.24", 1},
+		{"2.0.0", "1.28.5", 1},
+	}
+	for _, test := range tests {
+		a, _ := versionNumbers(test.a)
+		b, _ := versionNumbers(test.b)
+		if got := compareVersionNumbers(a, b); got != test.want {
+			t.Errorf("compareVersionNumbers(%s, %s) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+	for _, v := range []string{"", "2.0.0-BETA", "1.x"} {
+		if _, ok := versionNumbers(v); ok {
+			t.Errorf("versionNumbers(%q) ok = true, want false", v)
+		}
+	}
+}
+
+// signedRelease returns a KEYS file with a new public key, and an armored
+// detached signature of jar made with that key.
+func signedRelease(t *testing.T, jar []byte) (keys, sig []byte) {
+	t.Helper()
+	e, err := openpgp.NewEntity("Tika Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("error creating key: %v", err)
+	}
+	var keysBuf bytes.Buffer
+	fmt.Fprintln(&keysBuf, "This file contains the PGP keys of Tika release managers.")
+	w, err := armor.Encode(&keysBuf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("error encoding key: %v", err)
+	}
+	if err := e.Serialize(w); err != nil {
+		t.Fatalf("error encoding key: %v", err)
+	}
+	w.Close()
+	var sigBuf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&sigBuf, e, bytes.NewReader(jar), nil); err != nil {
+		t.Fatalf("error signing jar: %v", err)
+	}
+	return keysBuf.Bytes(), sigBuf.Bytes()
+}
+
+func TestDownloadServerSignature(t *testing.T) {
+	jar := []byte("not really a jar")
+	sum := sha512.Sum512(jar)
+	keys, sig := signedRelease(t, jar)
+	otherKeys, _ := signedRelease(t, jar)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch path.Ext(r.URL.Path) {
+		case ".jar":
+			w.Write(jar)
+		case ".sha512":
+			fmt.Fprintf(w, "%x", sum)
+		case ".asc":
+			w.Write(sig)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		keys    []byte
+		wantErr bool
+	}{
+		{name: "valid signature", keys: keys},
+		{name: "unknown signer", keys: otherKeys, wantErr: true},
+		{name: "no keys", keys: []byte("no keys here"), wantErr: true},
+	}
+	for _, test := range tests {
+		p := filepath.Join(dir, strings.Replace(test.name, " ", "_", -1)+".jar")
+		err := DownloadServer(context.Background(), "9.9", p,
+			WithPublishedChecksum(),
+			WithMirrors(ts.URL),
+			WithSignatureVerification(bytes.NewReader(test.keys)))
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("DownloadServer(%s) got no error, want an error", test.name)
+			}
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("DownloadServer(%s) kept the unverified JAR: %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("DownloadServer(%s) got error: %v", test.name, err)
+		}
+	}
+}
+
+func TestEnsureServer(t *testing.T) {
+	jar := []byte("not really a jar")
+	defer fakeMaven(t, jar)()
+	cache, err := ioutil.TempDir("", "cache")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(cache)
+	for _, env := range []string{"XDG_CACHE_HOME", "HOME", "LocalAppData"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Setenv(env, cache)
+	}
+	dir, err := CacheDir()
+	if err != nil {
+		t.Fatalf("CacheDir got error: %v", err)
+	}
+	if !strings.HasPrefix(dir, cache) {
+		t.Skipf("CacheDir() = %q is not in the test cache %q on this platform", dir, cache)
+	}
+
+	// Pin the checksum of the fake JAR for a made up release.
+	defer func(old []release) { releases = old }(releases)
+	releases = append(releases[:len(releases):len(releases)], release{"1.99", fmt.Sprintf("%x", sha512.Sum512(jar))})
+	const v = Version("1.99")
+	s, err := EnsureServer(context.Background(), v, "")
+	if err != nil {
+		t.Fatalf("EnsureServer got error: %v", err)
+	}
+	want := filepath.Join(dir, "tika-server-1.99.jar")
+	if s.jar != want {
+		t.Errorf("EnsureServer jar = %q, want %q", s.jar, want)
+	}
+	if s.version != v {
+		t.Errorf("EnsureServer version = %q, want %q", s.version, v)
+	}
+	if got, err := ioutil.ReadFile(want); err != nil || string(got) != string(jar) {
+		t.Errorf("EnsureServer saved %q (%v), want %q", got, err, jar)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import "time"
+
+// DublinCore is the Dublin Core metadata of a document. Fields Tika doesn't
+// report are empty.
+type DublinCore struct {
+	Title       string
+	Creator     []string
+	Subject     []string
+	Description string
+	Publisher   string
+	Contributor []string
+	// Date is when the document was created, and Modified when it was last
+	// modified.
+	Date       time.Time
+	Modified   time.Time
+	Type       string
+	Format     string
+	Identifier string
+	Source     string
+	Language   string
+	Relation   string
+	Coverage   string
+	Rights     string
+}
+
+// Keys of each Dublin Core element, in order of preference. Tika 2 reports
+// the dc: and dcterms: keys for every format, but Tika 1 and some parsers use
+// the other names.
+var (
+	dcTitleKeys       = []string{MetaTitle, "title", "Title"}
+	dcCreatorKeys     = []string{MetaCreator, "meta:author", "Author", "creator", "Creator", "pdf:docinfo:creator"}
+	dcSubjectKeys     = []string{MetaSubject, "meta:keyword", "Keywords", "subject", "pdf:docinfo:keywords"}
+	dcDescriptionKeys = []string{MetaDescription, "description", "cp:subject", "pdf:docinfo:subject", "Comments"}
+	dcPublisherKeys   = []string{"dc:publisher", "publisher"}
+	dcContributorKeys = []string{"dc:contributor", "contributor"}
+	dcDateKeys        = []string{MetaCreated, "dc:date", "meta:creation-date", "Creation-Date", "created", "date", "pdf:docinfo:created"}
+	dcModifiedKeys    = []string{MetaModified, "meta:save-date", "Last-Save-Date", "Last-Modified", "modified", "pdf:docinfo:modified"}
+	dcTypeKeys        = []string{"dc:type", "type"}
+	dcFormatKeys      = []string{"dc:format", MetaContentType}
+	dcIdentifierKeys  = []string{"dc:identifier", "identifier"}
+	dcSourceKeys      = []string{"dc:source", "source"}
+	dcLanguageKeys    = []string{MetaLanguage, "language", "Content-Language"}
+	dcRelationKeys    = []string{"dc:relation", "relation"}
+	dcCoverageKeys    = []string{"dc:coverage", "coverage"}
+	dcRightsKeys      = []string{"dc:rights", "rights"}
+)
+
+// DublinCore returns the Dublin Core metadata of the document, taking each
+// element from whichever of the keys Tika uses for it is present.
+func (m Metadata) DublinCore() DublinCore {
+	return DublinCore{
+		Title:       m.first(dcTitleKeys...),
+		Creator:     m.all(dcCreatorKeys...),
+		Subject:     m.all(dcSubjectKeys...),
+		Description: m.first(dcDescriptionKeys...),
+		Publisher:   m.first(dcPublisherKeys...),
+		Contributor: m.all(dcContributorKeys...),
+		Date:        m.firstDate(dcDateKeys...),
+		Modified:    m.firstDate(dcModifiedKeys...),
+		Type:        m.first(dcTypeKeys...),
+		Format:      m.first(dcFormatKeys...),
+		Identifier:  m.first(dcIdentifierKeys...),
+		Source:      m.first(dcSourceKeys...),
+		Language:    m.first(dcLanguageKeys...),
+		Relation:    m.first(dcRelationKeys...),
+		Coverage:    m.first(dcCoverageKeys...),
+		Rights:      m.first(dcRightsKeys...),
+	}
+}
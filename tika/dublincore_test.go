@@ -0,0 +1,96 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDublinCore(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	modified := time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		m    Metadata
+		want DublinCore
+	}{
+		{
+			name: "empty",
+			m:    Metadata{},
+			want: DublinCore{},
+		},
+		{
+			name: "dublin core keys",
+			m: Metadata{
+				"dc:title":         {"Report"},
+				"dc:creator":       {"Ann", "Bob"},
+				"dc:subject":       {"go", "tika"},
+				"dc:description":   {"A report"},
+				"dc:publisher":     {"ACME"},
+				"dc:language":      {"en"},
+				"dc:rights":        {"CC-BY"},
+				"dcterms:created":  {"2020-01-02T03:04:05Z"},
+				"dcterms:modified": {"2021-06-07"},
+				"Content-Type":     {"application/pdf"},
+				// Legacy aliases lose to the dc: keys.
+				"title":       {"Old title"},
+				"meta:author": {"Carol"},
+			},
+			want: DublinCore{
+				Title:       "Report",
+				Creator:     []string{"Ann", "Bob"},
+				Subject:     []string{"go", "tika"},
+				Description: "A report",
+				Publisher:   "ACME",
+				Language:    "en",
+				Rights:      "CC-BY",
+				Date:        created,
+				Modified:    modified,
+				Format:      "application/pdf",
+			},
+		},
+		{
+			name: "legacy keys",
+			m: Metadata{
+				"title":              {"Report"},
+				"Author":             {"Ann"},
+				"Keywords":           {"go, tika"},
+				"Comments":           {"A report"},
+				"meta:creation-date": {"2020-01-02T03:04:05Z"},
+				"Last-Save-Date":     {"not a date"},
+				"Last-Modified":      {"2021-06-07"},
+				"Content-Language":   {"en"},
+			},
+			want: DublinCore{
+				Title:       "Report",
+				Creator:     []string{"Ann"},
+				Subject:     []string{"go, tika"},
+				Description: "A report",
+				Language:    "en",
+				Date:        created,
+				Modified:    modified,
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := test.m.DublinCore(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: DublinCore() got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmailResult is an email message, such as an .eml or Outlook .msg file,
+// parsed by Email.
+type EmailResult struct {
+	From      string
+	To        []string
+	Cc        []string
+	Bcc       []string
+	Subject   string
+	MessageID string
+	InReplyTo string
+	// Sent is when the message was sent, or the zero time if unknown.
+	Sent time.Time
+	// Text is the body of the message.
+	Text        string
+	Attachments []Attachment
+	// Metadata is all metadata Tika reported for the message.
+	Metadata Metadata
+}
+
+// An Attachment is a file attached to an email message. Read it to get its
+// contents.
+type Attachment struct {
+	io.Reader
+	Name string
+	Size int64
+}
+
+// Email parses the email message in input, returning its headers, body and
+// attachments. It sends the message to Tika twice, once to /meta and once to
+// /unpack/all, so input is read into memory first. input may be nil with
+// WithFileURL. If the error is not nil, the result is nil.
+func (c *Client) Email(ctx context.Context, input io.Reader, opts ...RequestOption) (*EmailResult, error) {
+	var b []byte
+	if input != nil {
+		var err error
+		if b, err = ioutil.ReadAll(input); err != nil {
+			return nil, err
+		}
+	}
+	body := func() io.Reader {
+		if input == nil {
+			return nil
+		}
+		return bytes.NewReader(b)
+	}
+	m, err := c.MetaJSON(ctx, body(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	files, err := c.UnpackAll(ctx, body(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	e := newEmailResult(m)
+	e.Text = strings.TrimSpace(string(files[UnpackTextFile]))
+	delete(files, UnpackTextFile)
+	delete(files, UnpackMetadataFile)
+	for name, f := range files {
+		e.Attachments = append(e.Attachments, Attachment{
+			Reader: bytes.NewReader(f),
+			Name:   name,
+			Size:   int64(len(f)),
+		})
+	}
+	sort.Slice(e.Attachments, func(i, j int) bool { return e.Attachments[i].Name < e.Attachments[j].Name })
+	return e, nil
+}
+
+// newEmailResult returns the headers of the message with metadata m. The
+// RFC 822 and Outlook parsers report them under different keys.
+func newEmailResult(m Metadata) *EmailResult {
+	e := &EmailResult{
+		From:      m.first("Message-From", "Message:From-Email", MetaCreator),
+		To:        m.all("Message-To", "Message-Recipient-Address"),
+		Cc:        m.all("Message-Cc"),
+		Bcc:       m.all("Message-Bcc"),
+		Subject:   m.first(MetaTitle, "subject", MetaSubject),
+		MessageID: m.first("Message:Raw-Header:Message-ID", "Message-ID"),
+		InReplyTo: m.first("Message:Raw-Header:In-Reply-To", "In-Reply-To"),
+		Sent:      m.firstDate(MetaCreated, "meta:creation-date", "Creation-Date"),
+		Metadata:  m,
+	}
+	if e.Sent.IsZero() {
+		if t, err := mail.ParseDate(m.first("Message:Raw-Header:Date")); err == nil {
+			e.Sent = t
+		}
+	}
+	return e
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmail(t *testing.T) {
+	const msg = "From: ann@example.com\r\n\r\nHello"
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if b, _ := ioutil.ReadAll(r.Body); string(b) != msg {
+			t.Errorf("%s got body %q, want %q", r.URL.Path, b, msg)
+		}
+		switch r.URL.Path {
+		case "/meta":
+			fmt.Fprint(w, `{
+				"Message-From": "Ann <ann@example.com>",
+				"Message-To": ["bob@example.com", "carol@example.com"],
+				"Message-Cc": "dave@example.com",
+				"dc:title": "Hello",
+				"Message:Raw-Header:Message-ID": "<1@example.com>",
+				"Message:Raw-Header:Date": "Thu, 2 Jan 2020 03:04:05 +0000"
+			}`)
+		case "/unpack/all":
+			zw := zip.NewWriter(w)
+			for name, b := range map[string]string{
+				UnpackTextFile:     "  Hello\n",
+				UnpackMetadataFile: `"Content-Type","message/rfc822"`,
+				"b.pdf":            "pdf",
+				"a.txt":            "text",
+			} {
+				f, _ := zw.Create(name)
+				f.Write([]byte(b))
+			}
+			zw.Close()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+
+	got, err := c.Email(context.Background(), strings.NewReader(msg))
+	if err != nil {
+		t.Fatalf("Email got error: %v", err)
+	}
+	if want := []string{"/meta", "/unpack/all"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("Email requested %q, want %q", paths, want)
+	}
+	if got.From != "Ann <ann@example.com>" || got.Subject != "Hello" || got.MessageID != "<1@example.com>" || got.Text != "Hello" {
+		t.Errorf("Email got From %q, Subject %q, MessageID %q, Text %q", got.From, got.Subject, got.MessageID, got.Text)
+	}
+	if want := []string{"bob@example.com", "carol@example.com"}; !reflect.DeepEqual(got.To, want) {
+		t.Errorf("Email got To %q, want %q", got.To, want)
+	}
+	if want := []string{"dave@example.com"}; !reflect.DeepEqual(got.Cc, want) {
+		t.Errorf("Email got Cc %q, want %q", got.Cc, want)
+	}
+	if want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC); !got.Sent.Equal(want) {
+		t.Errorf("Email got Sent %v, want %v", got.Sent, want)
+	}
+	var names []string
+	for _, a := range got.Attachments {
+		b, _ := ioutil.ReadAll(a)
+		names = append(names, fmt.Sprintf("%s:%s:%d", a.Name, b, a.Size))
+	}
+	if want := []string{"a.txt:text:4", "b.pdf:pdf:3"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Email got attachments %q, want %q", names, want)
+	}
+
+	if _, err := errorClient.Email(context.Background(), strings.NewReader(msg)); err == nil {
+		t.Errorf("Email got no error, want an error")
+	}
+}
+
+func TestEmailFileURL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("fileUrl"), "file:///mail/a.eml"; got != want {
+			t.Errorf("%s got fileUrl %q, want %q", r.URL.Path, got, want)
+		}
+		switch r.URL.Path {
+		case "/meta":
+			fmt.Fprint(w, `{"dc:title": "Hello"}`)
+		case "/unpack/all":
+			zw := zip.NewWriter(w)
+			f, _ := zw.Create(UnpackTextFile)
+			f.Write([]byte("Hello"))
+			zw.Close()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+
+	got, err := c.Email(context.Background(), nil, WithFileURL("file:///mail/a.eml"))
+	if err != nil {
+		t.Fatalf("Email(WithFileURL) got error: %v", err)
+	}
+	if got.Subject != "Hello" || got.Text != "Hello" {
+		t.Errorf("Email(WithFileURL) got Subject %q, Text %q, want %q and %q", got.Subject, got.Text, "Hello", "Hello")
+	}
+}
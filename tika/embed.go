@@ -0,0 +1,118 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EmbeddedServer returns a new Server running jar, the contents of a server
+// JAR bundled into the program, for example with go:embed:
+//
+//	//go:embed tika-server-1.21.jar
+//	var jar []byte
+//
+//	s, err := tika.EmbeddedServer(jar, sha512Sum, "", tika.WithTikaVersion(tika.Version121))
+//
+// The JAR is written to CacheDir, or os.TempDir if there is no cache
+// directory, unless a copy with the same sha512 is already there. sha512Sum
+// is the hex encoded sha512 of the JAR; EmbeddedServer returns an error if
+// jar doesn't match it. The Server is not started. opts are passed to
+// NewServer.
+func EmbeddedServer(jar []byte, sha512Sum, port string, opts ...ServerOption) (*Server, error) {
+	path, err := ExtractServerJAR(bytes.NewReader(jar), sha512Sum, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(path, port, opts...)
+}
+
+// EmbeddedServerFS is like EmbeddedServer, but reads the JAR from the file
+// name in fsys, such as an embed.FS.
+func EmbeddedServerFS(fsys fs.FS, name, sha512Sum, port string, opts ...ServerOption) (*Server, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("error opening embedded jar: %v", err)
+	}
+	defer f.Close()
+	path, err := ExtractServerJAR(f, sha512Sum, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewServer(path, port, opts...)
+}
+
+// ExtractServerJAR writes the server JAR read from r to dir and returns its
+// path. If dir is empty, CacheDir is used, or os.TempDir if there is no cache
+// directory. The file is named after sha512Sum, so JARs of different versions
+// can share dir, and ExtractServerJAR doesn't rewrite a file that already has
+// the right sha512. An error is returned if the JAR doesn't match sha512Sum;
+// nothing is left in dir in that case.
+func ExtractServerJAR(r io.Reader, sha512Sum, dir string) (string, error) {
+	sha512Sum = strings.ToLower(strings.TrimSpace(sha512Sum))
+	if len(sha512Sum) != sha512.Size*2 {
+		return "", errors.New("invalid sha512 for embedded jar")
+	}
+	if dir == "" {
+		var err error
+		if dir, err = CacheDir(); err != nil {
+			dir = os.TempDir()
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating cache directory: %v", err)
+	}
+	path := filepath.Join(dir, "tika-server-"+sha512Sum[:16]+".jar")
+	if got, err := sha512Hash(path); err == nil && got == sha512Sum {
+		return path, nil
+	}
+
+	// Write to a temporary file in dir and rename it, so concurrent callers
+	// never see a partial JAR at path.
+	tmp, err := ioutil.TempFile(dir, "tika-server-*.part")
+	if err != nil {
+		return "", fmt.Errorf("error creating jar: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	h := sha512.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", fmt.Errorf("error writing jar: %v", err)
+	}
+	if got := fmt.Sprintf("%x", h.Sum(nil)); got != sha512Sum {
+		return "", fmt.Errorf("invalid sha512: %s", got)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+)
+
+func TestExtractServerJAR(t *testing.T) {
+	jar := []byte("not really a jar")
+	sum := fmt.Sprintf("%x", sha512.Sum512(jar))
+	dir := t.TempDir()
+
+	path, err := ExtractServerJAR(bytes.NewReader(jar), strings.ToUpper(sum), dir)
+	if err != nil {
+		t.Fatalf("ExtractServerJAR got error: %v", err)
+	}
+	if want := filepath.Join(dir, "tika-server-"+sum[:16]+".jar"); path != want {
+		t.Errorf("ExtractServerJAR path = %q, want %q", path, want)
+	}
+	if got, err := ioutil.ReadFile(path); err != nil || !bytes.Equal(got, jar) {
+		t.Errorf("ExtractServerJAR wrote %q (%v), want %q", got, err, jar)
+	}
+
+	// An existing JAR with the right sha512 is reused as is.
+	if _, err := ExtractServerJAR(iotest.ErrReader(errors.New("read")), sum, dir); err != nil {
+		t.Errorf("ExtractServerJAR with existing jar got error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		jar  string
+		sum  string
+	}{
+		{name: "wrong sha512", jar: "corrupted", sum: fmt.Sprintf("%x", sha512.Sum512([]byte("other")))},
+		{name: "invalid sha512", jar: "jar", sum: "abc"},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		if _, err := ExtractServerJAR(strings.NewReader(test.jar), test.sum, dir); err == nil {
+			t.Errorf("ExtractServerJAR(%s) got no error, want error", test.name)
+		}
+		if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+			t.Errorf("ExtractServerJAR(%s) left %d files in dir, want none", test.name, len(files))
+		}
+	}
+}
+
+func TestEmbeddedServer(t *testing.T) {
+	jar := []byte("not really a jar")
+	sum := fmt.Sprintf("%x", sha512.Sum512(jar))
+	cache := t.TempDir()
+	for _, env := range []string{"XDG_CACHE_HOME", "HOME", "LocalAppData"} {
+		t.Setenv(env, cache)
+	}
+
+	s, err := EmbeddedServer(jar, sum, "", WithTikaVersion(Version121))
+	if err != nil {
+		t.Fatalf("EmbeddedServer got error: %v", err)
+	}
+	if s.version != Version121 {
+		t.Errorf("EmbeddedServer version = %q, want %q", s.version, Version121)
+	}
+	if got, err := ioutil.ReadFile(s.jar); err != nil || !bytes.Equal(got, jar) {
+		t.Errorf("EmbeddedServer wrote %q (%v), want %q", got, err, jar)
+	}
+
+	fsys := fstest.MapFS{"tika-server.jar": &fstest.MapFile{Data: jar}}
+	s2, err := EmbeddedServerFS(fsys, "tika-server.jar", sum, "")
+	if err != nil {
+		t.Fatalf("EmbeddedServerFS got error: %v", err)
+	}
+	if s2.jar != s.jar {
+		t.Errorf("EmbeddedServerFS jar = %q, want %q", s2.jar, s.jar)
+	}
+	if _, err := EmbeddedServerFS(fsys, "missing.jar", sum, ""); err == nil {
+		t.Errorf("EmbeddedServerFS(missing.jar) got no error, want error")
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io"
+	"mime"
+	"strings"
+)
+
+// Metadata fields Tika sets when it detects the character encoding of a text
+// document.
+const (
+	MetaContentEncoding   = "Content-Encoding"
+	XTIKADetectedEncoding = "X-TIKA:detectedEncoding"
+	XTIKAEncodingDetector = "X-TIKA:encodingDetector"
+)
+
+// Encoding is the character encoding of a document.
+type Encoding struct {
+	// Charset is the name of the encoding, such as "UTF-8" or
+	// "windows-1252", or "" if Tika didn't detect one, for example because
+	// the document isn't text.
+	Charset string
+	// MediaType is the MIME type of the document without parameters, such
+	// as "text/plain".
+	MediaType string
+	// Detector is the Tika encoding detector that detected the encoding,
+	// such as "UniversalEncodingDetector", if the server reports it.
+	Detector string
+}
+
+// DetectEncoding detects the character encoding of the given input, for
+// example to re-encode a legacy text document as UTF-8. It requests the
+// metadata of the input. If the error is not nil, the result is undefined.
+func (c *Client) DetectEncoding(ctx context.Context, input io.Reader, opts ...RequestOption) (Encoding, error) {
+	m, err := c.MetaJSON(ctx, input, opts...)
+	if err != nil {
+		return Encoding{}, err
+	}
+	return m.Encoding(), nil
+}
+
+// Encoding returns the character encoding of the document. Depending on the
+// version and parser, Tika reports it as XTIKADetectedEncoding,
+// MetaContentEncoding or the charset parameter of MetaContentType.
+func (m Metadata) Encoding() Encoding {
+	e := Encoding{
+		Charset:  m.first(XTIKADetectedEncoding, MetaContentEncoding),
+		Detector: m.first(XTIKAEncodingDetector),
+	}
+	ct := m.ContentType()
+	mediaType, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		// Fall back to the type before any parameters.
+		mediaType = strings.TrimSpace(strings.SplitN(ct, ";", 2)[0])
+	}
+	e.MediaType = mediaType
+	if e.Charset == "" {
+		e.Charset = params["charset"]
+	}
+	return e
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetadataEncoding(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Metadata
+		want Encoding
+	}{
+		{"empty", Metadata{}, Encoding{}},
+		{"not text", Metadata{MetaContentType: {"application/pdf"}}, Encoding{MediaType: "application/pdf"}},
+		{
+			name: "content type charset",
+			m:    Metadata{MetaContentType: {"text/plain; charset=ISO-8859-1"}},
+			want: Encoding{Charset: "ISO-8859-1", MediaType: "text/plain"},
+		},
+		{
+			name: "content encoding",
+			m: Metadata{
+				MetaContentType:     {"text/html; charset=windows-1252"},
+				MetaContentEncoding: {"windows-1252"},
+			},
+			want: Encoding{Charset: "windows-1252", MediaType: "text/html"},
+		},
+		{
+			name: "detected encoding",
+			m: Metadata{
+				MetaContentType:       {"text/plain; charset=UTF-8"},
+				XTIKADetectedEncoding: {"UTF-8"},
+				XTIKAEncodingDetector: {"UniversalEncodingDetector"},
+			},
+			want: Encoding{Charset: "UTF-8", MediaType: "text/plain", Detector: "UniversalEncodingDetector"},
+		},
+		{
+			name: "malformed content type",
+			m:    Metadata{MetaContentType: {"text/plain; charset"}},
+			want: Encoding{MediaType: "text/plain"},
+		},
+	}
+	for _, test := range tests {
+		if got := test.m.Encoding(); got != test.want {
+			t.Errorf("%s: Encoding got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	var path, accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, accept = r.URL.Path, r.Header.Get("Accept")
+		fmt.Fprint(w, `{"Content-Type":"text/plain; charset=ISO-8859-1","Content-Encoding":"ISO-8859-1"}`)
+	}))
+	defer ts.Close()
+	got, err := NewClient(nil, ts.URL).DetectEncoding(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("DetectEncoding got error: %v", err)
+	}
+	if want := (Encoding{Charset: "ISO-8859-1", MediaType: "text/plain"}); got != want {
+		t.Errorf("DetectEncoding got %+v, want %+v", got, want)
+	}
+	if path != "/meta" || accept != "application/json" {
+		t.Errorf("DetectEncoding requested %q with Accept %q, want /meta with Accept application/json", path, accept)
+	}
+	if _, err := errorClient.DetectEncoding(context.Background(), nil); err == nil {
+		t.Errorf("DetectEncoding got no error, want an error")
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors for common Tika Server failures. An *Error with the matching status
+// code is reported as equal to them by errors.Is, for example:
+//
+//	if errors.Is(err, tika.ErrEncryptedDocument) {
+//		// Ask for a password.
+//	}
+var (
+	// ErrUnsupportedMediaType means Tika has no parser for the document type
+	// (415 Unsupported Media Type).
+	ErrUnsupportedMediaType = errors.New("tika: unsupported media type")
+	// ErrEncryptedDocument means Tika could not process the document, most
+	// often because it is password protected (422 Unprocessable Entity).
+	ErrEncryptedDocument = errors.New("tika: encrypted or unprocessable document")
+	// ErrServerBusy means the server is temporarily unable to handle the
+	// request and it should be retried later (503 Service Unavailable).
+	ErrServerBusy = errors.New("tika: server busy")
+	// ErrTooLarge means the document is larger than the server accepts (413
+	// Request Entity Too Large).
+	ErrTooLarge = errors.New("tika: document too large")
+)
+
+// statusErrors maps HTTP status codes to the errors they match.
+var statusErrors = map[int]error{
+	http.StatusUnsupportedMediaType:  ErrUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   ErrEncryptedDocument,
+	http.StatusServiceUnavailable:    ErrServerBusy,
+	http.StatusRequestEntityTooLarge: ErrTooLarge,
+}
+
+// maxErrorBody is the maximum number of bytes of a response body kept in an
+// Error.
+const maxErrorBody = 1024
+
+// An Error is returned by Client methods when the Tika Server responds with a
+// status code other than 200 OK.
+type Error struct {
+	// Method and Path are the HTTP method and path of the request, for example
+	// PUT and /tika.
+	Method string
+	Path   string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Body is the beginning of the response body, which often explains why
+	// the request failed.
+	Body string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("%s %s: response code %v", e.Method, e.Path, e.StatusCode)
+	if body := strings.TrimSpace(e.Body); body != "" {
+		msg += ": " + body
+	}
+	return msg
+}
+
+// Is reports whether target is the error matching the status code of e, like
+// ErrUnsupportedMediaType for 415 Unsupported Media Type.
+func (e *Error) Is(target error) bool {
+	err, ok := statusErrors[e.StatusCode]
+	return ok && err == target
+}
+
+// An UploadTooLargeError is returned when a document is larger than the
+// maximum set with WithMaxUploadSize. It matches ErrTooLarge with errors.Is.
+type UploadTooLargeError struct {
+	// Limit is the maximum upload size.
+	Limit int64
+	// Size is the size of the document, or -1 if it is only known to be
+	// larger than Limit.
+	Size int64
+}
+
+func (e *UploadTooLargeError) Error() string {
+	if e.Size < 0 {
+		return fmt.Sprintf("tika: document larger than the maximum upload size of %d bytes", e.Limit)
+	}
+	return fmt.Sprintf("tika: document of %d bytes larger than the maximum upload size of %d bytes", e.Size, e.Limit)
+}
+
+// Is reports whether target is ErrTooLarge.
+func (e *UploadTooLargeError) Is(target error) bool {
+	return target == ErrTooLarge
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte("org.apache.tika.exception.EncryptedDocumentException\n"))
+		w.Write([]byte(strings.Repeat("x", 2*maxErrorBody)))
+	}))
+	defer ts.Close()
+	_, err := NewClient(nil, ts.URL).Parse(context.Background(), nil)
+	e, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("Parse got error %v (%T), want an *Error", err, err)
+	}
+	if e.Method != "PUT" || e.Path != "/tika" || e.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("Parse got %s %s %d, want PUT /tika %d", e.Method, e.Path, e.StatusCode, http.StatusUnprocessableEntity)
+	}
+	if !strings.HasPrefix(e.Body, "org.apache.tika.exception.EncryptedDocumentException") || len(e.Body) != maxErrorBody {
+		t.Errorf("Parse got body %q, want the first %d bytes of the response", e.Body, maxErrorBody)
+	}
+	if want := "PUT /tika: response code 422: org.apache.tika"; !strings.HasPrefix(e.Error(), want) {
+		t.Errorf("Error() = %q, want prefix %q", e.Error(), want)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusUnsupportedMediaType, ErrUnsupportedMediaType},
+		{http.StatusUnprocessableEntity, ErrEncryptedDocument},
+		{http.StatusServiceUnavailable, ErrServerBusy},
+		{http.StatusRequestEntityTooLarge, ErrTooLarge},
+		{http.StatusInternalServerError, nil},
+	}
+	sentinels := []error{ErrUnsupportedMediaType, ErrEncryptedDocument, ErrServerBusy, ErrTooLarge}
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(test.status)
+		}))
+		_, err := NewClient(nil, ts.URL).Parse(context.Background(), nil)
+		ts.Close()
+		wrapped := fmt.Errorf("parsing file: %w", err)
+		for _, s := range sentinels {
+			if got, want := errors.Is(wrapped, s), s == test.want; got != want {
+				t.Errorf("errors.Is(%d error, %v) = %v, want %v", test.status, s, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// defaultFailoverCooldown is how long a server that failed is avoided.
+const defaultFailoverCooldown = 30 * time.Second
+
+// NewFailoverClient creates a new Client that sends requests to the servers at
+// urls, configured by opts. Requests go to the first server that is up. When a
+// server refuses the connection or responds with a 5xx status code, the
+// request is sent to the next server, and the failed server is avoided for a
+// cooldown period (see WithFailoverCooldown). If every server is down, they
+// are all tried anyway. Use WithLoadBalancing to spread requests over every
+// server instead of preferring the first.
+//
+// A request is only sent to another server if its body can be sent again, so
+// pass documents as an *os.File, *bytes.Reader or *strings.Reader, or use
+// WithRetry, which buffers other documents.
+func NewFailoverClient(urls []string, opts ...ClientOption) (*Client, error) {
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no servers specified")
+	}
+	f := &failover{cooldown: defaultFailoverCooldown}
+	for _, s := range urls {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %v", s, err)
+		}
+		f.endpoints = append(f.endpoints, &failoverEndpoint{url: u})
+	}
+	opts = append(opts, func(cfg *clientConfig) { cfg.failover = f })
+	return NewClientWithOptions(urls[0], opts...), nil
+}
+
+// WithLoadBalancing makes a Client created with NewFailoverClient send
+// requests to its servers in round-robin order, skipping servers that are
+// down.
+func WithLoadBalancing() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.balance = true
+	}
+}
+
+// WithFailoverCooldown sets how long a Client created with NewFailoverClient
+// avoids a server after a request to it failed. The default is 30 seconds.
+func WithFailoverCooldown(d time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.failoverCooldown = d
+	}
+}
+
+// failover is an http.RoundTripper that sends each request to the first
+// server that is up, and fails over to the others.
+type failover struct {
+	base      http.RoundTripper
+	endpoints []*failoverEndpoint
+	balance   bool
+	cooldown  time.Duration
+
+	mu   sync.Mutex
+	next int // The first endpoint to try when balancing.
+}
+
+// A failoverEndpoint is a server a failover sends requests to.
+type failoverEndpoint struct {
+	url       *url.URL
+	downUntil time.Time // Guarded by failover.mu.
+}
+
+// order returns the endpoints in the order to try them: the endpoints that
+// are up, then those that are down.
+func (f *failover) order() []*failoverEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	start := 0
+	if f.balance {
+		start = f.next
+		f.next = (f.next + 1) % len(f.endpoints)
+	}
+	now := time.Now()
+	var up, down []*failoverEndpoint
+	for i := range f.endpoints {
+		e := f.endpoints[(start+i)%len(f.endpoints)]
+		if now.Before(e.downUntil) {
+			down = append(down, e)
+		} else {
+			up = append(up, e)
+		}
+	}
+	return append(up, down...)
+}
+
+func (f *failover) setDown(e *failoverEndpoint, down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if down {
+		e.downUntil = time.Now().Add(f.cooldown)
+	} else {
+		e.downUntil = time.Time{}
+	}
+}
+
+// RoundTrip implements http.RoundTripper by sending req to each endpoint in
+// turn until one doesn't fail.
+func (f *failover) RoundTrip(req *http.Request) (*http.Response, error) {
+	order := f.order()
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for i, e := range order {
+		r := req.Clone(req.Context())
+		r.URL.Scheme = e.url.Scheme
+		r.URL.Host = e.url.Host
+		r.Host = e.url.Host
+		if i > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+		resp, err := f.base.RoundTrip(r)
+		failed := err != nil || resp.StatusCode >= 500
+		if req.Context().Err() != nil {
+			// The caller gave up, which says nothing about the server.
+			return resp, err
+		}
+		f.setDown(e, failed)
+		if !failed || i == len(order)-1 || !replayable {
+			return resp, err
+		}
+		if resp != nil {
+			io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			resp.Body.Close()
+		}
+	}
+	// Not reached, there is always at least one endpoint.
+	return nil, fmt.Errorf("no servers specified")
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// echoNameServer responds with name followed by the request body, or with
+// status while *fail is non-zero.
+func echoNameServer(name string, status int, fail *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(fail) != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s%s", name, body)
+	}))
+}
+
+func TestFailoverClient(t *testing.T) {
+	var fail1, fail2 int32
+	ts1 := echoNameServer("one", http.StatusServiceUnavailable, &fail1)
+	defer ts1.Close()
+	ts2 := echoNameServer("two", http.StatusInternalServerError, &fail2)
+	defer ts2.Close()
+	c, err := NewFailoverClient([]string{ts1.URL, ts2.URL}, WithFailoverCooldown(time.Hour))
+	if err != nil {
+		t.Fatalf("NewFailoverClient got error: %v", err)
+	}
+	parse := func() (string, error) {
+		return c.Parse(context.Background(), strings.NewReader(":doc"))
+	}
+
+	if got, err := parse(); err != nil || got != "one:doc" {
+		t.Errorf("Parse = %q, %v, want one:doc", got, err)
+	}
+	atomic.StoreInt32(&fail1, 1)
+	// The body is resent to the second server.
+	if got, err := parse(); err != nil || got != "two:doc" {
+		t.Errorf("Parse with the first server failing = %q, %v, want two:doc", got, err)
+	}
+	// The first server is avoided during the cooldown, even once it recovers.
+	atomic.StoreInt32(&fail1, 0)
+	if got, err := parse(); err != nil || got != "two:doc" {
+		t.Errorf("Parse during the cooldown = %q, %v, want two:doc", got, err)
+	}
+	// If every server is down, they are all tried.
+	atomic.StoreInt32(&fail2, 1)
+	if got, err := parse(); err != nil || got != "one:doc" {
+		t.Errorf("Parse with the second server failing = %q, %v, want one:doc", got, err)
+	}
+	atomic.StoreInt32(&fail1, 1)
+	_, err = parse()
+	// The second server is down, so it is tried last.
+	if e, ok := err.(*Error); !ok || e.StatusCode != http.StatusInternalServerError {
+		t.Errorf("Parse with every server failing got error %v, want the 500 of the last server tried", err)
+	}
+}
+
+func TestFailoverConnectionError(t *testing.T) {
+	var fail int32
+	ts := echoNameServer("up", 0, &fail)
+	defer ts.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+	c, err := NewFailoverClient([]string{down.URL, ts.URL})
+	if err != nil {
+		t.Fatalf("NewFailoverClient got error: %v", err)
+	}
+	if got, err := c.Version(context.Background()); err != nil || got != "up" {
+		t.Errorf("Version = %q, %v, want up", got, err)
+	}
+	// A body that can't be resent isn't sent to the next server.
+	if _, err := c.Parse(context.Background(), ioutil.NopCloser(strings.NewReader("doc"))); err != nil {
+		t.Errorf("Parse got error %v, want the healthy server to be tried first", err)
+	}
+}
+
+func TestFailoverLoadBalancing(t *testing.T) {
+	var fail1, fail2 int32
+	ts1 := echoNameServer("one", http.StatusServiceUnavailable, &fail1)
+	defer ts1.Close()
+	ts2 := echoNameServer("two", http.StatusServiceUnavailable, &fail2)
+	defer ts2.Close()
+	c, err := NewFailoverClient([]string{ts1.URL, ts2.URL}, WithLoadBalancing())
+	if err != nil {
+		t.Fatalf("NewFailoverClient got error: %v", err)
+	}
+	var got []string
+	for i := 0; i < 4; i++ {
+		v, err := c.Version(context.Background())
+		if err != nil {
+			t.Fatalf("Version got error: %v", err)
+		}
+		got = append(got, v)
+	}
+	if want := []string{"one", "two", "one", "two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Version responses = %q, want %q", got, want)
+	}
+}
+
+func TestNewFailoverClientErrors(t *testing.T) {
+	if _, err := NewFailoverClient(nil); err == nil {
+		t.Errorf("NewFailoverClient(nil) got no error")
+	}
+	if _, err := NewFailoverClient([]string{"http://a", ":"}); err == nil {
+		t.Errorf("NewFailoverClient with an invalid URL got no error")
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// WithMultipartForm sends the document as a multipart/form-data upload to
+// the form variant of the endpoint, for example POST /tika/form instead of
+// PUT /tika, for networks where proxies or firewalls alter raw request
+// bodies. The filename set with WithFilename and the Content-Type of the
+// document are sent in the file part. Parse, Meta and RecursiveMetadata and
+// the methods using them support forms; other methods send the document as
+// usual. Use WithRequestDefaults to send every document as a form.
+func WithMultipartForm() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.form = true
+	}
+}
+
+// formPath returns the path of the multipart form endpoint of path, and
+// whether there is one.
+func formPath(path string) (string, bool) {
+	switch {
+	case path == "/tika", path == "/meta":
+		return path + "/form", true
+	case path == "/rmeta", strings.HasPrefix(path, "/rmeta/"):
+		return "/rmeta/form" + strings.TrimPrefix(path, "/rmeta"), true
+	}
+	return "", false
+}
+
+// formBody replaces the body of req, and of every copy of it made for
+// retries, with a multipart/form-data body with the original body as its
+// only file. The Content-Disposition and Content-Type headers of req are
+// moved to the file part.
+func formBody(req *http.Request) error {
+	part := make(textproto.MIMEHeader)
+	disposition := `form-data; name="upload"`
+	if _, params, err := mime.ParseMediaType(req.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		disposition = mime.FormatMediaType("form-data", map[string]string{"name": "upload", "filename": params["filename"]})
+	}
+	part.Set("Content-Disposition", disposition)
+	contentType := req.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	part.Set("Content-Type", contentType)
+	req.Header.Del("Content-Disposition")
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if _, err := w.CreatePart(part); err != nil {
+		return err
+	}
+	head := append([]byte(nil), buf.Bytes()...)
+	buf.Reset()
+	if err := w.Close(); err != nil {
+		return err
+	}
+	tail := buf.Bytes()
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	wrap := func(body io.ReadCloser) io.ReadCloser {
+		if body == nil {
+			body = http.NoBody
+		}
+		return struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(head), body, bytes.NewReader(tail)), body}
+	}
+	switch {
+	case req.Body == nil || req.Body == http.NoBody:
+		req.ContentLength = int64(len(head) + len(tail))
+	case req.ContentLength > 0:
+		req.ContentLength += int64(len(head) + len(tail))
+	default:
+		req.ContentLength = -1
+	}
+	req.Body = wrap(req.Body)
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return wrap(body), nil
+		}
+	}
+	return nil
+}
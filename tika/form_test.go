@@ -0,0 +1,122 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMultipartForm(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		p, err := mr.NextPart()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if p.FormName() != "upload" {
+			http.Error(w, "bad part "+p.FormName(), http.StatusBadRequest)
+			return
+		}
+		b, _ := ioutil.ReadAll(p)
+		if _, err := mr.NextPart(); err != io.EOF {
+			http.Error(w, fmt.Sprintf("want one part, got %v", err), http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "%s %s name=%q type=%q len=%d body=%s", r.Method, r.URL.Path, p.FileName(), p.Header.Get("Content-Type"), r.ContentLength, b)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+
+	tests := []struct {
+		name  string
+		input io.Reader
+		opts  []RequestOption
+		want  string
+	}{
+		{
+			name:  "parse",
+			input: strings.NewReader("hello"),
+			want:  `POST /tika/form name="" type="application/octet-stream" len=`,
+		},
+		{
+			name:  "filename",
+			input: strings.NewReader("hello"),
+			opts:  []RequestOption{WithFilename("a b.txt"), WithHeader("Content-Type", "text/plain")},
+			want:  `POST /tika/form name="a b.txt" type="text/plain" len=`,
+		},
+		{
+			name:  "unknown length",
+			input: ioutil.NopCloser(strings.NewReader("hello")),
+			want:  `POST /tika/form name="" type="application/octet-stream" len=-1 body=hello`,
+		},
+	}
+	for _, test := range tests {
+		opts := append([]RequestOption{WithMultipartForm()}, test.opts...)
+		got, err := c.Parse(context.Background(), test.input, opts...)
+		if err != nil {
+			t.Errorf("Parse(%s) got error: %v", test.name, err)
+			continue
+		}
+		if !strings.HasPrefix(got, test.want) || !strings.HasSuffix(got, " body=hello") {
+			t.Errorf("Parse(%s) = %q, want %q...body=hello", test.name, got, test.want)
+		}
+		if strings.Contains(got, "len=0 ") {
+			t.Errorf("Parse(%s) = %q, want the content length of the form", test.name, got)
+		}
+	}
+
+	got, err := c.Meta(context.Background(), strings.NewReader("hello"), WithMultipartForm())
+	if err != nil {
+		t.Fatalf("Meta got error: %v", err)
+	}
+	if want := "POST /meta/form "; !strings.HasPrefix(got, want) {
+		t.Errorf("Meta = %q, want prefix %q", got, want)
+	}
+}
+
+func TestFormPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"/tika", "/tika/form", true},
+		{"/meta", "/meta/form", true},
+		{"/rmeta", "/rmeta/form", true},
+		{"/rmeta/text", "/rmeta/form/text", true},
+		{"/meta/Content-Type", "", false},
+		{"/detect/stream", "", false},
+	}
+	for _, test := range tests {
+		got, ok := formPath(test.path)
+		if got != test.want || ok != test.ok {
+			t.Errorf("formPath(%q) = %q, %v, want %q, %v", test.path, got, ok, test.want, test.ok)
+		}
+	}
+}
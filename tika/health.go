@@ -0,0 +1,183 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Ping checks that the server is responding to requests, by requesting its
+// version. Ping returns nil if it is.
+func (c *Client) Ping(ctx context.Context, opts ...RequestOption) error {
+	_, err := c.Version(ctx, opts...)
+	return err
+}
+
+// IsAlive reports whether the server is responding to requests, by sending
+// HEAD /tika. Unlike Ping, the server doesn't compute or send anything, so
+// IsAlive is cheap enough for frequent liveness probes. IsAlive doesn't check
+// that the server can parse documents.
+func (c *Client) IsAlive(ctx context.Context, opts ...RequestOption) bool {
+	return c.alive(ctx, opts) == nil
+}
+
+// alive is IsAlive, returning the error of a failed check.
+func (c *Client) alive(ctx context.Context, opts []RequestOption) error {
+	resp, err := c.do(ctx, nil, "HEAD", "/tika", nil, opts)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// A HealthStatus is the result of the health checks of a server.
+type HealthStatus int
+
+const (
+	// HealthUnknown means the server wasn't checked yet.
+	HealthUnknown HealthStatus = iota
+	// Healthy means the server responded to its latest check.
+	Healthy
+	// Unhealthy means the server failed its latest check.
+	Unhealthy
+)
+
+func (s HealthStatus) String() string {
+	switch s {
+	case Healthy:
+		return "healthy"
+	case Unhealthy:
+		return "unhealthy"
+	}
+	return "unknown"
+}
+
+// A HealthChecker pings a server in the background and reports when it
+// becomes healthy or unhealthy. Create a HealthChecker with NewHealthChecker,
+// subscribe to changes with OnChange, and start it with Start.
+type HealthChecker struct {
+	c        *Client
+	interval time.Duration
+
+	mu       sync.Mutex
+	status   HealthStatus
+	err      error
+	onChange []func(HealthStatus, error)
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewHealthChecker creates a new HealthChecker that checks the server of c
+// with IsAlive every interval. Each check times out after interval.
+func NewHealthChecker(c *Client, interval time.Duration) *HealthChecker {
+	return &HealthChecker{c: c, interval: interval}
+}
+
+// OnChange calls f whenever the status of the server changes, including
+// after the first check, with the error of the failed check if it became
+// Unhealthy. Calls are made one at a time from the goroutine checking the
+// server, so f must not block.
+func (h *HealthChecker) OnChange(f func(status HealthStatus, err error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onChange = append(h.onChange, f)
+}
+
+// Status returns the status of the server and, if it is Unhealthy, the error
+// of its latest check.
+func (h *HealthChecker) Status() (HealthStatus, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status, h.err
+}
+
+// Check checks the server once, updating its status, and returns it. If ctx
+// is done before the check finishes, the status is left unchanged.
+func (h *HealthChecker) Check(ctx context.Context) HealthStatus {
+	checkCtx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+	err := h.c.alive(checkCtx, nil)
+	if ctx.Err() != nil {
+		status, _ := h.Status()
+		return status
+	}
+	status := Healthy
+	if err != nil {
+		status = Unhealthy
+	}
+	h.mu.Lock()
+	changed := status != h.status
+	h.status, h.err = status, err
+	subs := h.onChange
+	h.mu.Unlock()
+	if changed {
+		for _, f := range subs {
+			f(status, err)
+		}
+	}
+	return status
+}
+
+// Start checks the server immediately and then every interval, until Stop is
+// called.
+func (h *HealthChecker) Start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stop != nil {
+		return
+	}
+	h.stop = make(chan struct{})
+	h.done = make(chan struct{})
+	go h.run(h.stop, h.done)
+}
+
+// Stop stops the checks and waits for a check in progress to finish.
+func (h *HealthChecker) Stop() {
+	h.mu.Lock()
+	stop, done := h.stop, h.done
+	h.stop, h.done = nil, nil
+	h.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+func (h *HealthChecker) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+	for {
+		h.Check(ctx)
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPing(t *testing.T) {
+	var fail int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			t.Errorf("got path %q, want /version", r.URL.Path)
+		}
+		if atomic.LoadInt32(&fail) != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("Apache Tika 2.9.2"))
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	if err := c.Ping(context.Background()); err != nil {
+		t.Errorf("Ping got error: %v", err)
+	}
+	atomic.StoreInt32(&fail, 1)
+	if err := c.Ping(context.Background()); err == nil {
+		t.Errorf("Ping of a failing server got no error")
+	}
+}
+
+func TestIsAlive(t *testing.T) {
+	var fail int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "HEAD" || r.URL.Path != "/tika" {
+			t.Errorf("got %s %s, want HEAD /tika", r.Method, r.URL.Path)
+		}
+		if atomic.LoadInt32(&fail) != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	if !c.IsAlive(context.Background()) {
+		t.Errorf("IsAlive = false, want true")
+	}
+	atomic.StoreInt32(&fail, 1)
+	if c.IsAlive(context.Background()) {
+		t.Errorf("IsAlive of a failing server = true, want false")
+	}
+	ts.Close()
+	if c.IsAlive(context.Background()) {
+		t.Errorf("IsAlive of a stopped server = true, want false")
+	}
+}
+
+func TestHealthChecker(t *testing.T) {
+	var fail int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&fail) != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("Apache Tika 2.9.2"))
+	}))
+	defer ts.Close()
+	h := NewHealthChecker(NewClient(nil, ts.URL), 10*time.Millisecond)
+	if s, _ := h.Status(); s != HealthUnknown {
+		t.Errorf("Status before Start = %v, want %v", s, HealthUnknown)
+	}
+	var mu sync.Mutex
+	var changes []HealthStatus
+	changed := make(chan struct{}, 10)
+	h.OnChange(func(s HealthStatus, err error) {
+		if (s == Unhealthy) != (err != nil) {
+			t.Errorf("OnChange(%v, %v), want an error only when unhealthy", s, err)
+		}
+		mu.Lock()
+		changes = append(changes, s)
+		mu.Unlock()
+		changed <- struct{}{}
+	})
+	wait := func(want HealthStatus) {
+		t.Helper()
+		select {
+		case <-changed:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no change to %v", want)
+		}
+		if s, _ := h.Status(); s != want {
+			t.Errorf("Status = %v, want %v", s, want)
+		}
+	}
+
+	h.Start()
+	defer h.Stop()
+	wait(Healthy)
+	atomic.StoreInt32(&fail, 1)
+	wait(Unhealthy)
+	if _, err := h.Status(); err == nil {
+		t.Errorf("Status of an unhealthy server got no error")
+	}
+	atomic.StoreInt32(&fail, 0)
+	wait(Healthy)
+	h.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := []HealthStatus{Healthy, Unhealthy, Healthy}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
+
+func TestHealthStatusString(t *testing.T) {
+	for s, want := range map[HealthStatus]string{HealthUnknown: "unknown", Healthy: "healthy", Unhealthy: "unhealthy"} {
+		if got := s.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", s, got, want)
+		}
+	}
+}
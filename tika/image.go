@@ -0,0 +1,94 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"strings"
+	"time"
+)
+
+// ImageMetadata is the metadata Tika's image parsers report for a photo or
+// other image, mostly taken from its EXIF tags. Fields Tika doesn't report
+// are zero.
+type ImageMetadata struct {
+	// Width and Height are in pixels.
+	Width  int
+	Height int
+	// Make and Model identify the camera.
+	Make  string
+	Model string
+	// Orientation is the EXIF orientation, from 1 (upright) to 8.
+	Orientation int
+	// HasLocation reports whether Latitude and Longitude, in decimal degrees,
+	// were recorded.
+	HasLocation bool
+	Latitude    float64
+	Longitude   float64
+	// Captured is when the photo was taken. EXIF has no time zone, so it is
+	// in UTC.
+	Captured     time.Time
+	ExposureTime time.Duration
+	FNumber      float64
+	// FocalLength is in millimetres.
+	FocalLength float64
+	ISO         int
+	Flash       bool
+}
+
+// exifDateLayout is the layout of dates in raw EXIF tags.
+const exifDateLayout = "2006:01:02 15:04:05"
+
+// ImageMetadata returns the image metadata of the document. Tika reports both
+// normalized keys, such as tiff:ImageWidth, and the raw tag names of the
+// underlying parser, such as "Image Width"; the normalized keys are preferred.
+func (m Metadata) ImageMetadata() ImageMetadata {
+	img := ImageMetadata{
+		Width:       int(m.firstInt("tiff:ImageWidth", "Image Width", "Exif Image Width", "width")),
+		Height:      int(m.firstInt("tiff:ImageLength", "Image Height", "Exif Image Height", "height")),
+		Make:        m.first("tiff:Make", "Make"),
+		Model:       m.first("tiff:Model", "Model"),
+		Orientation: int(m.firstInt("tiff:Orientation")),
+		FNumber:     m.firstFloatOrZero("exif:FNumber"),
+		FocalLength: m.firstFloatOrZero("exif:FocalLength", "Focal Length"),
+		ISO:         int(m.firstInt("exif:IsoSpeedRatings", "ISO Speed Ratings")),
+		Flash:       m.firstBool("exif:Flash"),
+	}
+	lat, okLat := m.firstFloat("geo:lat")
+	long, okLong := m.firstFloat("geo:long")
+	if okLat && okLong {
+		img.HasLocation, img.Latitude, img.Longitude = true, lat, long
+	}
+	if secs, ok := m.firstFloat("exif:ExposureTime", "Exposure Time"); ok {
+		img.ExposureTime = time.Duration(secs * float64(time.Second))
+	}
+	img.Captured = m.firstDate("exif:DateTimeOriginal", "dcterms:created")
+	if img.Captured.IsZero() {
+		for _, v := range m["Date/Time Original"] {
+			if t, err := time.Parse(exifDateLayout, strings.TrimSpace(v)); err == nil {
+				img.Captured = t
+				break
+			}
+		}
+	}
+	return img
+}
+
+// firstFloatOrZero is like firstFloat but returns 0 if there is no number.
+func (m Metadata) firstFloatOrZero(keys ...string) float64 {
+	f, _ := m.firstFloat(keys...)
+	return f
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestImageMetadata(t *testing.T) {
+	captured := time.Date(2009, 8, 11, 9, 9, 45, 0, time.UTC)
+	tests := []struct {
+		name string
+		m    Metadata
+		want ImageMetadata
+	}{
+		{
+			name: "empty",
+			m:    Metadata{},
+			want: ImageMetadata{},
+		},
+		{
+			name: "normalized keys",
+			m: Metadata{
+				"tiff:ImageWidth":       {"4000"},
+				"tiff:ImageLength":      {"3000"},
+				"tiff:Make":             {"Canon"},
+				"tiff:Model":            {"Canon EOS 5D"},
+				"tiff:Orientation":      {"6"},
+				"geo:lat":               {"51.507351"},
+				"geo:long":              {"-0.127758"},
+				"exif:DateTimeOriginal": {"2009-08-11T09:09:45"},
+				"exif:ExposureTime":     {"0.004"},
+				"exif:FNumber":          {"5.6"},
+				"exif:FocalLength":      {"50.0"},
+				"exif:IsoSpeedRatings":  {"400"},
+				"exif:Flash":            {"false"},
+				// Raw tags lose to the normalized keys.
+				"Image Width": {"10 pixels"},
+			},
+			want: ImageMetadata{
+				Width:        4000,
+				Height:       3000,
+				Make:         "Canon",
+				Model:        "Canon EOS 5D",
+				Orientation:  6,
+				HasLocation:  true,
+				Latitude:     51.507351,
+				Longitude:    -0.127758,
+				Captured:     captured,
+				ExposureTime: 4 * time.Millisecond,
+				FNumber:      5.6,
+				FocalLength:  50,
+				ISO:          400,
+			},
+		},
+		{
+			name: "raw tags",
+			m: Metadata{
+				"Image Width":        {"640 pixels"},
+				"Image Height":       {"480 pixels"},
+				"Make":               {"NIKON"},
+				"Orientation":        {"Top, left side (Horizontal / normal)"},
+				"Date/Time Original": {"2009:08:11 09:09:45"},
+				"Exposure Time":      {"1/250 sec"},
+				"Focal Length":       {"7.4 mm"},
+				"ISO Speed Ratings":  {"100"},
+				"geo:lat":            {"51.5"},
+			},
+			want: ImageMetadata{
+				Width:        640,
+				Height:       480,
+				Make:         "NIKON",
+				Captured:     captured,
+				ExposureTime: 4 * time.Millisecond,
+				FocalLength:  7.4,
+				ISO:          100,
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := test.m.ImageMetadata(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ImageMetadata() got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
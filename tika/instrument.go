@@ -0,0 +1,106 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// callStats are collected while a request is sent.
+type callStats struct {
+	sent int64 // Accessed atomically.
+}
+
+// countingBody is a request body that adds the number of bytes read to n.
+type countingBody struct {
+	io.ReadCloser
+	n *int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddInt64(b.n, int64(n))
+	return n, err
+}
+
+// instrumented is like c.doRequest, but traces the request with c.tracer,
+// measures it with c.metrics and logs it to c.logger, if they are set.
+func (c *Client) instrumented(ctx context.Context, input io.Reader, method, path string, header http.Header, opts []RequestOption) (*http.Response, error) {
+	start := time.Now()
+	ep := endpointOf(path)
+	var span Span
+	if c.tracer != nil {
+		ctx, span = c.tracer.Start(ctx, "tika "+method+" "+ep)
+		span.SetAttribute(AttrHTTPMethod, method)
+		span.SetAttribute(AttrTikaEndpoint, ep)
+		span.SetAttribute(AttrTikaPath, path)
+		if l, ok := input.(interface{ Len() int }); ok {
+			span.SetAttribute(AttrHTTPRequestSize, l.Len())
+		}
+	}
+	stats := &callStats{}
+	done := func(status int, err error) {
+		if span != nil {
+			if status != 0 {
+				span.SetAttribute(AttrHTTPStatusCode, status)
+			}
+			span.End(err)
+		}
+		if c.metrics != nil {
+			c.metrics.RequestDone(RequestStats{
+				Endpoint:   ep,
+				StatusCode: status,
+				BytesSent:  atomic.LoadInt64(&stats.sent),
+				Duration:   time.Since(start),
+				Err:        err,
+			})
+		}
+		if c.logger != nil {
+			level, attrs := slog.LevelDebug, []slog.Attr{
+				slog.String("method", method),
+				slog.String("endpoint", ep),
+				slog.Int("status", status),
+				slog.Duration("duration", time.Since(start)),
+				slog.Int64("bytes_sent", atomic.LoadInt64(&stats.sent)),
+			}
+			if err != nil {
+				level = slog.LevelWarn
+				attrs = append(attrs, slog.Any("error", err))
+			}
+			c.logger.LogAttrs(ctx, level, "tika request", attrs...)
+		}
+	}
+
+	resp, err := c.doRequest(ctx, input, method, path, header, opts, stats)
+	if err != nil {
+		status := 0
+		var e *Error
+		if errors.As(err, &e) {
+			status = e.StatusCode
+		}
+		done(status, err)
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() { done(resp.StatusCode, nil) }}
+	return resp, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FindJava returns the path of the Java binary. FindJava uses
+// $JAVA_HOME/bin/java if JAVA_HOME is set, and otherwise looks up java in the
+// PATH. On Windows, it then looks for the JavaHome of the installed JDKs and
+// JREs in the registry.
+func FindJava() (string, error) {
+	if home := os.Getenv("JAVA_HOME"); home != "" {
+		java := filepath.Join(home, "bin", javaBinary)
+		if _, err := os.Stat(java); err != nil {
+			return "", fmt.Errorf("JAVA_HOME is set to %q, but it does not contain bin/%s: %v", home, javaBinary, err)
+		}
+		return java, nil
+	}
+	java, err := exec.LookPath("java")
+	if err == nil {
+		return java, nil
+	}
+	for _, home := range registryJavaHomes() {
+		java := filepath.Join(home, "bin", javaBinary)
+		if _, statErr := os.Stat(java); statErr == nil {
+			return java, nil
+		}
+	}
+	return "", fmt.Errorf("java not found: install Java or set JAVA_HOME: %v", err)
+}
+
+// parseJavaHomes returns the JavaHome values in the output of
+// reg query /s /v JavaHome, most recently listed first, as the newest
+// version is usually listed last.
+func parseJavaHomes(out []byte) []string {
+	var homes []string
+	for _, line := range strings.Split(string(out), "\n") {
+		f := strings.Fields(line)
+		if len(f) < 3 || f[0] != "JavaHome" || !strings.HasPrefix(f[1], "REG_") {
+			continue
+		}
+		// The value may contain spaces, as in C:\Program Files\Java.
+		i := strings.Index(line, f[1]) + len(f[1])
+		homes = append([]string{strings.TrimSpace(line[i:])}, homes...)
+	}
+	return homes
+}
+
+// JavaVersion runs java -version and returns the major version of Java, for
+// example 8 for Java 1.8 and 11 for Java 11.
+func JavaVersion(ctx context.Context, java string) (int, error) {
+	// java -version prints to stderr, but be lenient about where it goes.
+	out, err := exec.CommandContext(ctx, java, "-version").CombinedOutput()
+	if err != nil {
+		return 0, fmt.Errorf("error running %s -version: %v", java, err)
+	}
+	return parseJavaVersion(out)
+}
+
+var javaVersionRE = regexp.MustCompile(`version "(\d+)(?:\.(\d+))?`)
+
+// parseJavaVersion parses the major version from the output of java -version.
+func parseJavaVersion(out []byte) (int, error) {
+	m := javaVersionRE.FindSubmatch(out)
+	if m == nil {
+		line := string(bytes.SplitN(out, []byte("\n"), 2)[0])
+		return 0, fmt.Errorf("unrecognized java -version output: %q", strings.TrimSpace(line))
+	}
+	major, err := strconv.Atoi(string(m[1]))
+	if err != nil {
+		return 0, err
+	}
+	// Before Java 9, versions were reported as 1.x.
+	if major == 1 && len(m[2]) > 0 {
+		return strconv.Atoi(string(m[2]))
+	}
+	return major, nil
+}
+
+// MinJavaVersion returns the minimum major Java version required to run the
+// given version of Tika Server.
+func MinJavaVersion(v Version) int {
+	if strings.HasPrefix(string(v), "1.") || strings.HasPrefix(string(v), "2.") {
+		return 8
+	}
+	return 11
+}
+
+// checkJava resolves the Java binary to use (finding it with FindJava if java
+// is empty) and verifies it is at least version min. It is a variable so tests
+// can run without Java installed.
+var checkJava = func(ctx context.Context, java string, min int) (string, error) {
+	if java == "" {
+		var err error
+		if java, err = FindJava(); err != nil {
+			return "", err
+		}
+	}
+	v, err := JavaVersion(ctx, java)
+	if err != nil {
+		return "", err
+	}
+	if v < min {
+		return "", fmt.Errorf("%s is Java %d, but Java %d or newer is required", java, v, min)
+	}
+	return java, nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// realCheckJava is checkJava before it is replaced in init.
+var realCheckJava = checkJava
+
+func TestParseJavaVersion(t *testing.T) {
+	tests := []struct {
+		out  string
+		want int
+	}{
+		{`java version "1.8.0_191"`, 8},
+		{"openjdk version \"11.0.2\" 2019-01-15\nOpenJDK Runtime Environment 18.9 (build 11.0.2+9)", 11},
+		{`openjdk version "17" 2021-09-14`, 17},
+		{`openjdk version "21-ea" 2023-09-19`, 21},
+	}
+	for _, test := range tests {
+		got, err := parseJavaVersion([]byte(test.out))
+		if err != nil {
+			t.Errorf("parseJavaVersion(%q) got error: %v", test.out, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseJavaVersion(%q) = %d, want %d", test.out, got, test.want)
+		}
+	}
+	if _, err := parseJavaVersion([]byte("command not found")); err == nil {
+		t.Errorf("parseJavaVersion got no error for invalid output, want an error")
+	}
+}
+
+func TestMinJavaVersion(t *testing.T) {
+	tests := []struct {
+		v    Version
+		want int
+	}{
+		{Version119, 8},
+		{"2.9.0", 8},
+		{"3.0.0", 11},
+	}
+	for _, test := range tests {
+		if got := MinJavaVersion(test.v); got != test.want {
+			t.Errorf("MinJavaVersion(%s) = %d, want %d", test.v, got, test.want)
+		}
+	}
+}
+
+// fakeJavaHome creates a JAVA_HOME with a bin/java that reports version.
+func fakeJavaHome(t *testing.T, version string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake java requires a POSIX shell")
+	}
+	home, err := ioutil.TempDir("", "java_home")
+	if err != nil {
+		t.Fatalf("error creating JAVA_HOME: %v", err)
+	}
+	if err := os.Mkdir(filepath.Join(home, "bin"), 0755); err != nil {
+		t.Fatalf("error creating JAVA_HOME: %v", err)
+	}
+	script := "#!/bin/sh\necho 'openjdk version \"" + version + "\"' >&2\n"
+	if err := ioutil.WriteFile(filepath.Join(home, "bin", "java"), []byte(script), 0755); err != nil {
+		t.Fatalf("error creating java: %v", err)
+	}
+	return home
+}
+
+func TestCheckJava(t *testing.T) {
+	home := fakeJavaHome(t, "11.0.2")
+	defer os.RemoveAll(home)
+	defer os.Setenv("JAVA_HOME", os.Getenv("JAVA_HOME"))
+	os.Setenv("JAVA_HOME", home)
+
+	java, err := realCheckJava(context.Background(), "", 8)
+	if err != nil {
+		t.Fatalf("checkJava got error: %v", err)
+	}
+	if want := filepath.Join(home, "bin", "java"); java != want {
+		t.Errorf("checkJava = %q, want %q", java, want)
+	}
+	if _, err := realCheckJava(context.Background(), "", 17); err == nil {
+		t.Errorf("checkJava(17) got no error for Java 11, want an error")
+	}
+
+	os.Setenv("JAVA_HOME", filepath.Join(home, "missing"))
+	if _, err := realCheckJava(context.Background(), "", 8); err == nil {
+		t.Errorf("checkJava with invalid JAVA_HOME got no error, want an error")
+	}
+}
+
+func TestParseJavaHomes(t *testing.T) {
+	out := []byte("\r\n" +
+		"HKEY_LOCAL_MACHINE\\SOFTWARE\\JavaSoft\\JDK\\11.0.2\r\n" +
+		"    JavaHome    REG_SZ    C:\\Program Files\\Java\\jdk-11.0.2\r\n" +
+		"\r\n" +
+		"HKEY_LOCAL_MACHINE\\SOFTWARE\\JavaSoft\\JDK\\17.0.1\r\n" +
+		"    JavaHome    REG_SZ    C:\\Program Files\\Java\\jdk-17.0.1\r\n" +
+		"\r\n" +
+		"End of search: 2 match(es) found.\r\n")
+	want := []string{`C:\Program Files\Java\jdk-17.0.1`, `C:\Program Files\Java\jdk-11.0.2`}
+	if got := parseJavaHomes(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseJavaHomes got %q, want %q", got, want)
+	}
+	if got := parseJavaHomes(nil); got != nil {
+		t.Errorf("parseJavaHomes(nil) got %q, want nil", got)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"os/exec"
+)
+
+// javaBinary is the name of the Java binary in $JAVA_HOME/bin.
+const javaBinary = "java.exe"
+
+// javaRegistryKeys are the registry keys the Java installers record the
+// JavaHome of each installed version under.
+var javaRegistryKeys = []string{
+	`HKLM\SOFTWARE\JavaSoft\JDK`,
+	`HKLM\SOFTWARE\JavaSoft\Java Development Kit`,
+	`HKLM\SOFTWARE\JavaSoft\JRE`,
+	`HKLM\SOFTWARE\JavaSoft\Java Runtime Environment`,
+	`HKLM\SOFTWARE\Eclipse Adoptium\JDK`,
+}
+
+// registryJavaHomes returns the JavaHome of every Java installation recorded
+// in the registry. It runs reg.exe rather than reading the registry directly,
+// to avoid a dependency on golang.org/x/sys.
+func registryJavaHomes() []string {
+	var homes []string
+	for _, key := range javaRegistryKeys {
+		out, err := exec.Command("reg", "query", key, "/s", "/v", "JavaHome").Output()
+		if err != nil {
+			continue // The key doesn't exist.
+		}
+		homes = append(homes, parseJavaHomes(out)...)
+	}
+	return homes
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	var mu sync.Mutex
+	var inFlight, maxInFlight int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		fmt.Fprint(w, "1.21")
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithMaxConcurrentRequests(2))
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Version(context.Background()); err != nil {
+				t.Errorf("Version got error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if maxInFlight > 2 {
+		t.Errorf("server saw %d concurrent requests, want at most 2", maxInFlight)
+	}
+	if len(c.sem) != 0 {
+		t.Errorf("%d requests still hold a slot after finishing, want 0", len(c.sem))
+	}
+}
+
+func TestMaxConcurrentRequestsContext(t *testing.T) {
+	c := NewClientWithOptions("http://localhost:0", WithMaxConcurrentRequests(1))
+	c.sem <- struct{}{} // Occupy the only slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Version(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Version got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "1.21")
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithRateLimit(50, 2))
+	start := time.Now()
+	for i := 0; i < 7; i++ {
+		if _, err := c.Version(context.Background()); err != nil {
+			t.Fatalf("Version got error: %v", err)
+		}
+	}
+	// The first 2 requests use the burst, the other 5 wait 20ms each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("7 requests took %v, want at least 100ms", elapsed)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithStructuredLogger logs the requests the Client sends to l. Successful
+// requests are logged at debug level and failed requests at warning level,
+// with the method, endpoint, status code, duration and size of the request.
+// By default, the Client doesn't log.
+func WithStructuredLogger(l *slog.Logger) ClientOption {
+	return func(c *clientConfig) {
+		c.logger = l
+	}
+}
+
+// WithServerLogger logs the lifecycle of the server to l: when it starts,
+// fails to start, stops or exits unexpectedly. A Supervisor also logs crashes
+// and restarts of the server to l. Unlike WithLogger, the output of the server
+// process itself is not logged.
+func WithServerLogger(l *slog.Logger) ServerOption {
+	return func(s *Server) {
+		s.logger = l
+	}
+}
+
+// WithDownloadLogger logs the progress of DownloadServer to l. Progress is
+// logged at debug level every 10 percent, if the size of the download is
+// known.
+func WithDownloadLogger(l *slog.Logger) DownloadOption {
+	return func(c *downloadConfig) {
+		c.logger = l
+	}
+}
+
+// logAttrs logs msg to l, if it is not nil.
+func logAttrs(ctx context.Context, l *slog.Logger, level slog.Level, msg string, attrs ...slog.Attr) {
+	if l != nil {
+		l.LogAttrs(ctx, level, msg, attrs...)
+	}
+}
+
+// logProgress returns a progress function for download that logs to l every
+// 10 percent and calls f, if it is not nil.
+func logProgress(l *slog.Logger, url string, f func(downloaded, total int64)) func(downloaded, total int64) {
+	if l == nil {
+		return f
+	}
+	logged := int64(-1)
+	return func(downloaded, total int64) {
+		if total > 0 {
+			if pct := downloaded * 100 / total / 10 * 10; pct > logged {
+				logged = pct
+				l.Debug("download progress", "url", url, "downloaded", downloaded, "total", total, "percent", pct)
+			}
+		}
+		if f != nil {
+			f(downloaded, total)
+		}
+	}
+}
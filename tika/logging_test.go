@@ -0,0 +1,129 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	h := slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	return slog.New(h)
+}
+
+func TestWithStructuredLogger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/meta/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "test value")
+	}))
+	defer ts.Close()
+	var buf bytes.Buffer
+	c := NewClientWithOptions(ts.URL, WithStructuredLogger(newTestLogger(&buf)))
+
+	if _, err := c.Version(context.Background()); err != nil {
+		t.Fatalf("Version got error: %v", err)
+	}
+	if _, err := c.MetaField(context.Background(), strings.NewReader("input"), "missing"); err == nil {
+		t.Fatalf("MetaField(missing) got no error")
+	}
+	want := []string{
+		`level=DEBUG msg="tika request" method=GET endpoint=/version status=200 bytes_sent=0`,
+		`level=WARN msg="tika request" method=PUT endpoint=/meta status=404 bytes_sent=5 error=`,
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("logged %q, want %d lines", lines, len(want))
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(lines[i], w) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestWithServerLogger(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	ts := bouncyServer(2)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	var buf bytes.Buffer
+	s, err := NewServer(path, tsURL.Port(), WithServerLogger(newTestLogger(&buf)))
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	s.Stop()
+	got := buf.String()
+	for _, want := range []string{
+		`level=INFO msg="starting tika server" url=` + s.URL(),
+		`level=INFO msg="tika server started" url=` + s.URL(),
+		`level=INFO msg="tika server stopped" url=` + s.URL(),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("logged %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "unexpectedly") {
+		t.Errorf("logged %q, want no unexpected exit", got)
+	}
+}
+
+func TestLogProgress(t *testing.T) {
+	var buf bytes.Buffer
+	var calls int
+	f := logProgress(newTestLogger(&buf), "u", func(downloaded, total int64) { calls++ })
+	for _, n := range []int64{0, 5, 10, 15, 99, 100} {
+		f(n, 100)
+	}
+	if calls != 6 {
+		t.Errorf("progress called %d times, want 6", calls)
+	}
+	if got, want := strings.Count(buf.String(), "download progress"), 4; got != want {
+		t.Errorf("logged %d progress lines, want %d:\n%s", got, want, buf.String())
+	}
+	if f := logProgress(nil, "u", nil); f != nil {
+		t.Errorf("logProgress(nil, nil) = non-nil, want nil")
+	}
+}
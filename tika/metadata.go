@@ -0,0 +1,293 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata fields set by Tika when parsing recursively. See XTIKAContent for
+// the content of each document.
+const (
+	// XTIKAEmbeddedResourcePath is the path of an embedded document within
+	// the container, for example "/attachment.zip/report.pdf".
+	XTIKAEmbeddedResourcePath = "X-TIKA:embedded_resource_path"
+)
+
+// XTIKADigestPrefix prefixes the metadata fields of the digests computed by a
+// server started with WithDigest, as in "X-TIKA:digest:SHA256".
+const XTIKADigestPrefix = "X-TIKA:digest:"
+
+// Common metadata fields. Tika normalizes the fields of most formats to these
+// names.
+const (
+	MetaContentType   = "Content-Type"
+	MetaContentLength = "Content-Length"
+	MetaTitle         = "dc:title"
+	MetaCreator       = "dc:creator"
+	MetaDescription   = "dc:description"
+	MetaSubject       = "dc:subject"
+	MetaLanguage      = "dc:language"
+	MetaCreated       = "dcterms:created"
+	MetaModified      = "dcterms:modified"
+	MetaPageCount     = "xmpTPg:NPages"
+	MetaResourceName  = "resourceName"
+)
+
+// Metadata is the metadata of a single document, mapping each field to its
+// values.
+type Metadata map[string][]string
+
+// Get returns the first value of key, or "" if there is none.
+func (m Metadata) Get(key string) string {
+	if v := m[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// GetAll returns all values of key.
+func (m Metadata) GetAll(key string) []string {
+	return m[key]
+}
+
+// Has reports whether m has a value for key.
+func (m Metadata) Has(key string) bool {
+	return len(m[key]) > 0
+}
+
+// Title returns the MetaTitle of the document.
+func (m Metadata) Title() string {
+	return m.Get(MetaTitle)
+}
+
+// Author returns the first MetaCreator of the document. Use GetAll to get
+// every author.
+func (m Metadata) Author() string {
+	return m.Get(MetaCreator)
+}
+
+// ContentType returns the MetaContentType of the document, for example
+// "application/pdf".
+func (m Metadata) ContentType() string {
+	return m.Get(MetaContentType)
+}
+
+// Created returns the MetaCreated date of the document.
+func (m Metadata) Created() (time.Time, error) {
+	return m.Date(MetaCreated)
+}
+
+// dateLayouts are the layouts Tika uses for dates, most precise first.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Date parses the first value of key as a date, such as MetaCreated. Dates
+// without a time zone are in UTC.
+func (m Metadata) Date(key string) (time.Time, error) {
+	v := m.Get(key)
+	if v == "" {
+		return time.Time{}, fmt.Errorf("no value for %q", key)
+	}
+	if t, ok := parseDate(v); ok {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("field %q has value %q, expected a date", key, v)
+}
+
+// parseDate parses v using one of dateLayouts.
+func parseDate(v string) (time.Time, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// first returns the first value of the first of keys that has one.
+func (m Metadata) first(keys ...string) string {
+	for _, k := range keys {
+		if v := strings.TrimSpace(m.Get(k)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// all returns every value of the first of keys that has any.
+func (m Metadata) all(keys ...string) []string {
+	for _, k := range keys {
+		if vs := m[k]; len(vs) > 0 {
+			return vs
+		}
+	}
+	return nil
+}
+
+// firstInt returns the first value of keys that is a number, truncated to
+// an integer, or 0 if there is none.
+func (m Metadata) firstInt(keys ...string) int64 {
+	f, _ := m.firstFloat(keys...)
+	return int64(f)
+}
+
+// firstFloat returns the first value of keys that is a number. Units after
+// the number, as in "640 pixels", are ignored, and fractions such as "1/250"
+// are divided out.
+func (m Metadata) firstFloat(keys ...string) (float64, bool) {
+	for _, k := range keys {
+		for _, v := range m[k] {
+			if f, ok := parseNumber(v); ok {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseNumber parses the leading number of v, which may be a fraction.
+func parseNumber(v string) (float64, bool) {
+	fields := strings.Fields(v)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	num, den := fields[0], ""
+	if i := strings.Index(num, "/"); i >= 0 {
+		num, den = num[:i], num[i+1:]
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, false
+	}
+	if den != "" {
+		d, err := strconv.ParseFloat(den, 64)
+		if err != nil || d == 0 {
+			return 0, false
+		}
+		f /= d
+	}
+	return f, true
+}
+
+// firstBool returns the first value of keys that is a boolean, or false if
+// there is none.
+func (m Metadata) firstBool(keys ...string) bool {
+	for _, k := range keys {
+		for _, v := range m[k] {
+			if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+				return b
+			}
+		}
+	}
+	return false
+}
+
+// firstDate returns the first value of keys that is a date, or the zero time
+// if there is none.
+func (m Metadata) firstDate(keys ...string) time.Time {
+	for _, k := range keys {
+		for _, v := range m[k] {
+			if t, ok := parseDate(strings.TrimSpace(v)); ok {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Int parses the first value of key as an integer, such as MetaPageCount.
+func (m Metadata) Int(key string) (int64, error) {
+	v := m.Get(key)
+	if v == "" {
+		return 0, fmt.Errorf("no value for %q", key)
+	}
+	i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("field %q has value %q, expected an integer", key, v)
+	}
+	return i, nil
+}
+
+// Digest returns the digest of the document computed by the server with the
+// given algorithm, such as "sha256", or "" if there is none. See WithDigest.
+func (m Metadata) Digest(algorithm string) string {
+	return m.Get(XTIKADigestPrefix + strings.ToUpper(algorithm))
+}
+
+// Digests returns every digest of the document computed by the server, keyed
+// by algorithm, for example "SHA256". It returns nil if there are none.
+func (m Metadata) Digests() map[string]string {
+	var d map[string]string
+	for k, v := range m {
+		if strings.HasPrefix(k, XTIKADigestPrefix) && len(v) > 0 {
+			if d == nil {
+				d = make(map[string]string)
+			}
+			d[strings.TrimPrefix(k, XTIKADigestPrefix)] = v[0]
+		}
+	}
+	return d
+}
+
+// Content returns the content of the document, or "" if there is none.
+func (m Metadata) Content() string {
+	return m.Get(XTIKAContent)
+}
+
+// EmbeddedPath returns the path of the document within its container, or ""
+// for the container itself.
+func (m Metadata) EmbeddedPath() string {
+	return m.Get(XTIKAEmbeddedResourcePath)
+}
+
+// A ContentFormat is the format of the XTIKAContent field returned by
+// RecursiveMetadata.
+type ContentFormat string
+
+// Content formats supported by the /rmeta endpoint.
+const (
+	ContentXML    ContentFormat = "xml"
+	ContentHTML   ContentFormat = "html"
+	ContentText   ContentFormat = "text"
+	ContentIgnore ContentFormat = "ignore" // Don't return any content.
+)
+
+// RecursiveMetadata parses the given input and all embedded documents,
+// returning the Metadata of each document, starting with the container. The
+// content of each document is in the given format. If the error is not nil,
+// the result is undefined.
+func (c *Client) RecursiveMetadata(ctx context.Context, input io.Reader, format ContentFormat, opts ...RequestOption) ([]Metadata, error) {
+	docs, err := c.MetaRecursiveType(ctx, input, string(format), opts...)
+	if err != nil {
+		return nil, err
+	}
+	r := make([]Metadata, len(docs))
+	for i, d := range docs {
+		r[i] = d
+	}
+	return r, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecursiveMetadata(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `[{"X-TIKA:content":"outer"},{"X-TIKA:content":"inner","X-TIKA:embedded_resource_path":"/a.txt"}]`)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	for _, format := range []ContentFormat{ContentXML, ContentHTML, ContentText, ContentIgnore} {
+		got, err := c.RecursiveMetadata(context.Background(), nil, format)
+		if err != nil {
+			t.Errorf("RecursiveMetadata(%q) got error: %v", format, err)
+			continue
+		}
+		if want := "/rmeta/" + string(format); path != want {
+			t.Errorf("RecursiveMetadata(%q) requested %q, want %q", format, path, want)
+		}
+		if len(got) != 2 {
+			t.Errorf("RecursiveMetadata(%q) got %d documents, want 2", format, len(got))
+			continue
+		}
+		if got[0].Content() != "outer" || got[0].EmbeddedPath() != "" {
+			t.Errorf("RecursiveMetadata(%q) got container %v, want content %q and no path", format, got[0], "outer")
+		}
+		if got[1].Content() != "inner" || got[1].EmbeddedPath() != "/a.txt" {
+			t.Errorf("RecursiveMetadata(%q) got embedded document %v, want content %q and path %q", format, got[1], "inner", "/a.txt")
+		}
+	}
+	if _, err := errorClient.RecursiveMetadata(context.Background(), nil, ContentText); err == nil {
+		t.Errorf("RecursiveMetadata got no error, want an error")
+	}
+}
+
+func TestMetadataEmpty(t *testing.T) {
+	var m Metadata
+	if got := m.Content(); got != "" {
+		t.Errorf("Content of nil Metadata got %q, want \"\"", got)
+	}
+	if got := m.EmbeddedPath(); got != "" {
+		t.Errorf("EmbeddedPath of nil Metadata got %q, want \"\"", got)
+	}
+}
+
+func TestMetadataDigest(t *testing.T) {
+	m := Metadata{
+		"X-TIKA:digest:MD5":    {"d41d8cd9"},
+		"X-TIKA:digest:SHA256": {"e3b0c442"},
+		MetaContentType:        {"text/plain"},
+	}
+	if got, want := m.Digest("sha256"), "e3b0c442"; got != want {
+		t.Errorf("Digest(sha256) got %q, want %q", got, want)
+	}
+	if got := m.Digest("sha1"); got != "" {
+		t.Errorf("Digest(sha1) got %q, want \"\"", got)
+	}
+	if got, want := m.Digests(), map[string]string{"MD5": "d41d8cd9", "SHA256": "e3b0c442"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Digests got %v, want %v", got, want)
+	}
+	if got := (Metadata{}).Digests(); got != nil {
+		t.Errorf("Digests of empty Metadata got %v, want nil", got)
+	}
+}
+
+func TestMetadataAccessors(t *testing.T) {
+	m := Metadata{
+		MetaCreator:   {"Alice", "Bob"},
+		MetaCreated:   {"2019-03-01T10:20:30Z"},
+		MetaModified:  {"2019-03-02"},
+		MetaPageCount: {"12"},
+		MetaTitle:     {"not a number"},
+	}
+	if got, want := m.Get(MetaCreator), "Alice"; got != want {
+		t.Errorf("Get(%q) got %q, want %q", MetaCreator, got, want)
+	}
+	if got, want := m.GetAll(MetaCreator), []string{"Alice", "Bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAll(%q) got %q, want %q", MetaCreator, got, want)
+	}
+	if !m.Has(MetaCreator) || m.Has(MetaSubject) {
+		t.Errorf("Has got %v and %v, want true and false", m.Has(MetaCreator), m.Has(MetaSubject))
+	}
+
+	dateTests := []struct {
+		key     string
+		want    time.Time
+		wantErr bool
+	}{
+		{key: MetaCreated, want: time.Date(2019, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{key: MetaModified, want: time.Date(2019, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{key: MetaTitle, wantErr: true},
+		{key: MetaSubject, wantErr: true},
+	}
+	for _, test := range dateTests {
+		got, err := m.Date(test.key)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("Date(%q) got error %v, want error: %v", test.key, err, test.wantErr)
+			continue
+		}
+		if !got.Equal(test.want) {
+			t.Errorf("Date(%q) got %v, want %v", test.key, got, test.want)
+		}
+	}
+
+	if got, err := m.Int(MetaPageCount); err != nil || got != 12 {
+		t.Errorf("Int(%q) got (%d, %v), want (12, nil)", MetaPageCount, got, err)
+	}
+	for _, key := range []string{MetaTitle, MetaSubject} {
+		if _, err := m.Int(key); err == nil {
+			t.Errorf("Int(%q) got no error, want an error", key)
+		}
+	}
+}
+
+func TestMetadataWellKnownFields(t *testing.T) {
+	m := Metadata{
+		MetaTitle:       {"Report"},
+		MetaCreator:     {"Alice", "Bob"},
+		MetaContentType: {"application/pdf"},
+		MetaCreated:     {"2019-03-01T10:20:30Z"},
+	}
+	if got, want := m.Title(), "Report"; got != want {
+		t.Errorf("Title got %q, want %q", got, want)
+	}
+	if got, want := m.Author(), "Alice"; got != want {
+		t.Errorf("Author got %q, want %q", got, want)
+	}
+	if got, want := m.ContentType(), "application/pdf"; got != want {
+		t.Errorf("ContentType got %q, want %q", got, want)
+	}
+	want := time.Date(2019, 3, 1, 10, 20, 30, 0, time.UTC)
+	if got, err := m.Created(); err != nil || !got.Equal(want) {
+		t.Errorf("Created got (%v, %v), want (%v, nil)", got, err, want)
+	}
+}
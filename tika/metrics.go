@@ -0,0 +1,85 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import "time"
+
+// Metrics receives measurements of Client operations, for example to export
+// them to Prometheus. go-tika doesn't depend on a metrics library; implement
+// Metrics with the library of your choice, for example:
+//
+//	type promMetrics struct {
+//		requests *prometheus.CounterVec   // Labels: endpoint, status.
+//		sent     *prometheus.CounterVec   // Labels: endpoint.
+//		latency  *prometheus.HistogramVec // Labels: endpoint.
+//		retries  *prometheus.CounterVec   // Labels: endpoint.
+//		open     prometheus.Gauge
+//	}
+//
+//	func (m *promMetrics) RequestDone(r tika.RequestStats) {
+//		m.requests.WithLabelValues(r.Endpoint, strconv.Itoa(r.StatusCode)).Inc()
+//		m.sent.WithLabelValues(r.Endpoint).Add(float64(r.BytesSent))
+//		m.latency.WithLabelValues(r.Endpoint).Observe(r.Duration.Seconds())
+//	}
+//
+//	func (m *promMetrics) Retried(endpoint string) {
+//		m.retries.WithLabelValues(endpoint).Inc()
+//	}
+//
+//	func (m *promMetrics) CircuitBreakerChanged(open bool) {
+//		if open {
+//			m.open.Set(1)
+//		} else {
+//			m.open.Set(0)
+//		}
+//	}
+//
+// Methods may be called concurrently. See WithMetrics.
+type Metrics interface {
+	// RequestDone is called when a Client call has finished, after its
+	// response body has been read and closed.
+	RequestDone(RequestStats)
+	// Retried is called when a request to endpoint is retried. See
+	// WithRetry.
+	Retried(endpoint string)
+	// CircuitBreakerChanged is called when the circuit breaker opens or
+	// closes. See WithCircuitBreaker.
+	CircuitBreakerChanged(open bool)
+}
+
+// RequestStats describes a finished Client call.
+type RequestStats struct {
+	// Endpoint is the Tika endpoint called, such as /tika or /meta.
+	Endpoint string
+	// StatusCode is the response code, or 0 if there was no response.
+	StatusCode int
+	// BytesSent is the size of the request bodies sent, including retries
+	// and after compression.
+	BytesSent int64
+	// Duration is the time from starting the call until the response body
+	// was closed.
+	Duration time.Duration
+	// Err is the error the call failed with, or nil.
+	Err error
+}
+
+// WithMetrics reports measurements of every Client call to m.
+func WithMetrics(m Metrics) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.metrics = m
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeMetrics records the measurements it receives.
+type fakeMetrics struct {
+	mu       sync.Mutex
+	requests []RequestStats
+	retries  []string
+	breaker  []bool
+}
+
+func (m *fakeMetrics) RequestDone(r RequestStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, r)
+}
+
+func (m *fakeMetrics) Retried(endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries = append(m.retries, endpoint)
+}
+
+func (m *fakeMetrics) CircuitBreakerChanged(open bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.breaker = append(m.breaker, open)
+}
+
+func TestWithMetrics(t *testing.T) {
+	failed := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/meta/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "test value")
+	}))
+	defer ts.Close()
+	m := &fakeMetrics{}
+	c := NewClientWithOptions(ts.URL,
+		WithMetrics(m),
+		WithRetry(RetryPolicy{InitialBackoff: 1}),
+		WithCircuitBreaker(1, 0),
+	)
+
+	input := "input"
+	if _, err := c.Parse(context.Background(), strings.NewReader(input)); err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if _, err := c.MetaField(context.Background(), nil, "missing"); err == nil {
+		t.Fatalf("MetaField got no error, want an error")
+	}
+
+	if len(m.requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(m.requests))
+	}
+	got := m.requests[0]
+	if got.Endpoint != "/tika" || got.StatusCode != 200 || got.BytesSent != int64(2*len(input)) || got.Err != nil || got.Duration <= 0 {
+		t.Errorf("Parse got %+v, want endpoint /tika, status 200 and %d bytes sent", got, 2*len(input))
+	}
+	got = m.requests[1]
+	if got.Endpoint != "/meta" || got.StatusCode != 404 || got.Err == nil {
+		t.Errorf("MetaField got %+v, want endpoint /meta, status 404 and an error", got)
+	}
+	if want := []string{"/tika"}; !reflect.DeepEqual(m.retries, want) {
+		t.Errorf("got retries %q, want %q", m.retries, want)
+	}
+	if want := []bool{true, false}; !reflect.DeepEqual(m.breaker, want) {
+		t.Errorf("got circuit breaker changes %v, want %v", m.breaker, want)
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import "net/http"
+
+// A Doer sends an HTTP request and returns the response, like
+// http.Client.Do.
+type Doer func(*http.Request) (*http.Response, error)
+
+// A Middleware wraps every HTTP request the Client sends, for example to add
+// authentication, log or measure requests. It calls next to send the request,
+// and may modify the request before and inspect the response after:
+//
+//	logRequests := func(next tika.Doer) tika.Doer {
+//		return func(req *http.Request) (*http.Response, error) {
+//			start := time.Now()
+//			resp, err := next(req)
+//			log.Printf("%s %s took %v", req.Method, req.URL.Path, time.Since(start))
+//			return resp, err
+//		}
+//	}
+//	c := tika.NewClientWithOptions(url, tika.WithMiddleware(logRequests))
+//
+// The response is the one from the server, before the Client converts non-200
+// responses to an *Error. If the Client retries a request, the middleware is
+// called for each attempt.
+type Middleware func(next Doer) Doer
+
+// WithMiddleware adds middleware to the Client. The first middleware is the
+// outermost: it sees the request first and the response last.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.middleware = append(cfg.middleware, mw...)
+	}
+}
+
+// chain returns do wrapped by mw, with mw[0] outermost.
+func chain(do Doer, mw []Middleware) Doer {
+	for i := len(mw) - 1; i >= 0; i-- {
+		do = mw[i](do)
+	}
+	return do
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var gotAuth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer ts.Close()
+
+	var calls []string
+	var gotStatus int
+	record := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next(req)
+				calls = append(calls, name+" response")
+				return resp, err
+			}
+		}
+	}
+	auth := func(next Doer) Doer {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Authorization", "Bearer token")
+			resp, err := next(req)
+			if err == nil {
+				gotStatus = resp.StatusCode
+			}
+			return resp, err
+		}
+	}
+	c := NewClientWithOptions(ts.URL, WithMiddleware(record("outer"), record("inner")), WithMiddleware(auth))
+	if _, err := c.Version(context.Background()); err == nil {
+		t.Errorf("Version got no error, want an error")
+	}
+	want := []string{"outer request", "inner request", "inner response", "outer response"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("middleware got calls %q, want %q", calls, want)
+	}
+	if gotAuth != "Bearer token" {
+		t.Errorf("server got Authorization %q, want %q", gotAuth, "Bearer token")
+	}
+	if gotStatus != http.StatusTeapot {
+		t.Errorf("middleware got status %d, want %d", gotStatus, http.StatusTeapot)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TextNormalization post-processes the text returned by Parse, ParsePlain,
+// ParseLimited and ParseRecursive. The zero value leaves text unchanged.
+type TextNormalization struct {
+	// StripControl removes control characters other than newlines and tabs,
+	// such as the form feeds Tika emits between PDF pages.
+	StripControl bool
+	// Dehyphenate joins words hyphenated across a line break, as in
+	// "extrac-\ntion".
+	Dehyphenate bool
+	// CollapseWhitespace replaces each run of spaces and tabs with a single
+	// space, trims the ends of lines and replaces runs of blank lines with a
+	// single blank line.
+	CollapseWhitespace bool
+	// Unicode, if not nil, is applied last. Use it for Unicode normalization,
+	// for example norm.NFC.String from golang.org/x/text/unicode/norm.
+	Unicode func(string) string
+}
+
+// WithTextNormalization post-processes parsed text with n. For example:
+//
+//	c := tika.NewClientWithOptions(url, tika.WithTextNormalization(tika.TextNormalization{
+//		StripControl:       true,
+//		CollapseWhitespace: true,
+//		Unicode:            norm.NFC.String,
+//	}))
+func WithTextNormalization(n TextNormalization) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.normalize = &n
+	}
+}
+
+var (
+	hyphenatedBreak = regexp.MustCompile(`(\pL)-[ \t]*\r?\n[ \t]*(\p{Ll})`)
+	blankLines      = regexp.MustCompile(`\n{3,}`)
+)
+
+// Normalize returns s post-processed by n.
+func (n TextNormalization) Normalize(s string) string {
+	if n.StripControl {
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) && r != '\n' && r != '\t' {
+				return -1
+			}
+			return r
+		}, s)
+	}
+	if n.Dehyphenate {
+		s = hyphenatedBreak.ReplaceAllString(s, "$1$2")
+	}
+	if n.CollapseWhitespace {
+		lines := strings.Split(s, "\n")
+		for i, l := range lines {
+			lines[i] = strings.Join(strings.Fields(l), " ")
+		}
+		s = blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+		s = strings.TrimSpace(s)
+	}
+	if n.Unicode != nil {
+		s = n.Unicode(s)
+	}
+	return s
+}
+
+// normalizeText post-processes parsed text with the normalization of c, if
+// any.
+func (c *Client) normalizeText(s string) string {
+	if c.normalize == nil {
+		return s
+	}
+	return c.normalize.Normalize(s)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTextNormalization(t *testing.T) {
+	const in = "  The extrac-\n  tion of\ttext \f\x00works.\n\n\n\nDone - Mid-\nCaps  \n"
+	tests := []struct {
+		name string
+		n    TextNormalization
+		want string
+	}{
+		{"none", TextNormalization{}, in},
+		{"strip control", TextNormalization{StripControl: true}, "  The extrac-\n  tion of\ttext works.\n\n\n\nDone - Mid-\nCaps  \n"},
+		{"dehyphenate", TextNormalization{Dehyphenate: true}, "  The extraction of\ttext \f\x00works.\n\n\n\nDone - Mid-\nCaps  \n"},
+		{"collapse whitespace", TextNormalization{CollapseWhitespace: true}, "The extrac-\ntion of text \x00works.\n\nDone - Mid-\nCaps"},
+		{
+			name: "all",
+			n: TextNormalization{
+				StripControl:       true,
+				Dehyphenate:        true,
+				CollapseWhitespace: true,
+				Unicode:            strings.ToUpper,
+			},
+			want: "THE EXTRACTION OF TEXT WORKS.\n\nDONE - MID-\nCAPS",
+		},
+	}
+	for _, test := range tests {
+		if got := test.n.Normalize(in); got != test.want {
+			t.Errorf("%s: Normalize got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWithTextNormalization(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rmeta/text" {
+			fmt.Fprint(w, `[{"X-TIKA:content":" a  b "}]`)
+			return
+		}
+		fmt.Fprint(w, " a  b ")
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithTextNormalization(TextNormalization{CollapseWhitespace: true}))
+	ctx := context.Background()
+	const want = "a b"
+	if got, err := c.Parse(ctx, nil); err != nil || got != want {
+		t.Errorf("Parse got (%q, %v), want (%q, nil)", got, err, want)
+	}
+	if got, err := c.ParsePlain(ctx, nil); err != nil || got != want {
+		t.Errorf("ParsePlain got (%q, %v), want (%q, nil)", got, err, want)
+	}
+	if got, _, err := c.ParseLimited(ctx, nil, 100); err != nil || got != want {
+		t.Errorf("ParseLimited got (%q, %v), want (%q, nil)", got, err, want)
+	}
+	if got, err := c.ParseRecursive(ctx, nil); err != nil || len(got) != 1 || got[0] != want {
+		t.Errorf("ParseRecursive got (%q, %v), want ([%q], nil)", got, err, want)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An OCRStrategy tells the PDF parser when to run OCR. See WithPDFOCRStrategy.
+type OCRStrategy string
+
+// OCR strategies supported by the PDF parser.
+const (
+	// OCRStrategyNoOCR only extracts the text layer.
+	OCRStrategyNoOCR OCRStrategy = "no_ocr"
+	// OCRStrategyOCROnly ignores the text layer and runs OCR on every page.
+	OCRStrategyOCROnly OCRStrategy = "ocr_only"
+	// OCRStrategyOCRAndText extracts the text layer and runs OCR on every
+	// page.
+	OCRStrategyOCRAndText OCRStrategy = "ocr_and_text_extraction"
+	// OCRStrategyAuto runs OCR on pages with little or no text layer.
+	OCRStrategyAuto OCRStrategy = "auto"
+)
+
+// WithOCRLanguage sets the Tesseract languages used for OCR, for example
+// WithOCRLanguage("eng", "fra"). The language data must be installed on the
+// Tika Server.
+func WithOCRLanguage(langs ...string) RequestOption {
+	return setHeader("X-Tika-OCRLanguage", strings.Join(langs, "+"))
+}
+
+// WithPDFOCRStrategy sets when the PDF parser runs OCR.
+func WithPDFOCRStrategy(s OCRStrategy) RequestOption {
+	return setHeader("X-Tika-PDFOcrStrategy", string(s))
+}
+
+// WithPDFOCRDPI sets the resolution PDF pages are rendered at before OCR.
+// Higher resolutions are slower but more accurate for small print.
+func WithPDFOCRDPI(dpi int) RequestOption {
+	return setHeader("X-Tika-PDFOcrDPI", strconv.Itoa(dpi))
+}
+
+// WithOCRTimeout sets how long Tesseract may spend on a single image, rounded
+// up to a whole number of seconds. Tika 2.x servers expect
+// X-Tika-OCRtimeoutSeconds instead; set it with WithHeader.
+func WithOCRTimeout(d time.Duration) RequestOption {
+	secs := int((d + time.Second - 1) / time.Second)
+	return setHeader("X-Tika-OCRTimeout", strconv.Itoa(secs))
+}
+
+// WithOCRPageSegMode sets the Tesseract page segmentation mode (the --psm
+// flag), for example "1" for automatic segmentation with orientation and
+// script detection or "6" for a single block of text.
+func WithOCRPageSegMode(mode string) RequestOption {
+	return setHeader("X-Tika-OCRPageSegMode", mode)
+}
+
+// TesseractOCRParser is the Java class name of Tika's OCR parser.
+const TesseractOCRParser = "org.apache.tika.parser.ocr.TesseractOCRParser"
+
+// OCRSupport reports whether a Tika Server can OCR images. See ProbeOCR.
+type OCRSupport struct {
+	// Available reports whether the server has a working Tesseract. Tika
+	// only registers the types TesseractOCRParser supports if it can run
+	// Tesseract.
+	Available bool
+	// Languages are the probed languages whose Tesseract data is installed.
+	Languages []string
+}
+
+// ProbeOCR reports whether the server can OCR images, so that scanned
+// documents can be routed to servers that can. If OCR is available, each of
+// langs, such as "eng" or "fra", is probed by OCRing a small blank image with
+// WithOCRLanguage; Tesseract fails for languages whose data isn't installed.
+func (c *Client) ProbeOCR(ctx context.Context, langs []string, opts ...RequestOption) (*OCRSupport, error) {
+	p, err := c.Parsers(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &OCRSupport{}
+	if ocr := p.Find(TesseractOCRParser); ocr != nil && len(ocr.SupportedTypes) > 0 {
+		s.Available = true
+	}
+	if !s.Available {
+		return s, nil
+	}
+	img, err := ocrProbeImage()
+	if err != nil {
+		return nil, err
+	}
+	for _, lang := range langs {
+		langOpts := append(append([]RequestOption(nil), opts...), WithOCRLanguage(lang))
+		_, err := c.Parse(ctx, bytes.NewReader(img), langOpts...)
+		var e *Error
+		switch {
+		case err == nil:
+			s.Languages = append(s.Languages, lang)
+		case errors.As(err, &e):
+			// The server failed to OCR with lang.
+		default:
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// ocrProbeImage returns a small blank PNG image to OCR.
+func ocrProbeImage() ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, 64, 32))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
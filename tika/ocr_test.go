@@ -0,0 +1,104 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOCROptions(t *testing.T) {
+	cfg := newRequestConfig(nil, []RequestOption{
+		WithOCRLanguage("eng", "fra"),
+		WithPDFOCRStrategy(OCRStrategyOCRAndText),
+		WithPDFOCRDPI(300),
+		WithOCRTimeout(1500 * time.Millisecond),
+		WithOCRPageSegMode("6"),
+	})
+	want := http.Header{
+		"X-Tika-Ocrlanguage":    {"eng+fra"},
+		"X-Tika-Pdfocrstrategy": {"ocr_and_text_extraction"},
+		"X-Tika-Pdfocrdpi":      {"300"},
+		"X-Tika-Ocrtimeout":     {"2"},
+		"X-Tika-Ocrpagesegmode": {"6"},
+	}
+	if !reflect.DeepEqual(cfg.header, want) {
+		t.Errorf("OCR options got header %v, want %v", cfg.header, want)
+	}
+}
+
+func TestProbeOCR(t *testing.T) {
+	tests := []struct {
+		name    string
+		parsers string
+		want    *OCRSupport
+	}{
+		{
+			name:    "available",
+			parsers: `{"name":"DefaultParser","composite":true,"children":[{"name":"` + TesseractOCRParser + `","supportedTypes":["image/png"]}]}`,
+			want:    &OCRSupport{Available: true, Languages: []string{"eng"}},
+		},
+		{
+			name:    "no tesseract",
+			parsers: `{"name":"DefaultParser","composite":true,"children":[{"name":"` + TesseractOCRParser + `","supportedTypes":[]}]}`,
+			want:    &OCRSupport{},
+		},
+		{
+			name:    "no parser",
+			parsers: `{"name":"DefaultParser","composite":true}`,
+			want:    &OCRSupport{},
+		},
+	}
+	for _, test := range tests {
+		var types []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/parsers/details":
+				fmt.Fprint(w, test.parsers)
+			case "/tika":
+				b, _ := ioutil.ReadAll(r.Body)
+				types = append(types, http.DetectContentType(b))
+				if r.Header.Get("X-Tika-OCRLanguage") != "eng" {
+					http.Error(w, "no language data", http.StatusUnprocessableEntity)
+				}
+			}
+		}))
+		got, err := NewClient(nil, ts.URL).ProbeOCR(context.Background(), []string{"eng", "xyz"})
+		ts.Close()
+		if err != nil {
+			t.Errorf("%s: ProbeOCR got error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ProbeOCR got %+v, want %+v", test.name, got, test.want)
+		}
+		for _, ct := range types {
+			if ct != "image/png" {
+				t.Errorf("%s: ProbeOCR sent %s, want image/png", test.name, ct)
+			}
+		}
+	}
+	if _, err := errorClient.ProbeOCR(context.Background(), nil); err == nil {
+		t.Errorf("ProbeOCR got no error, want an error")
+	}
+}
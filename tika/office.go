@@ -0,0 +1,70 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import "time"
+
+// OfficeMetadata is the metadata Tika's Microsoft Office parsers report for
+// OOXML documents, such as DOCX, XLSX and PPTX, and legacy OLE2 documents,
+// such as DOC, XLS and PPT. Fields Tika doesn't report are zero.
+type OfficeMetadata struct {
+	// Application is the program that wrote the document, such as
+	// "Microsoft Office Word".
+	Application    string
+	Author         string
+	LastModifiedBy string
+	Revision       int
+	Company        string
+	Manager        string
+	Template       string
+	Created        time.Time
+	Modified       time.Time
+	WordCount      int
+	CharacterCount int
+	// PageCount is the number of pages of a word processing document, and
+	// SlideCount the number of slides of a presentation.
+	PageCount  int
+	SlideCount int
+	// EditTime is the total time spent editing the document.
+	EditTime time.Duration
+}
+
+// OfficeMetadata returns the Office metadata of the document. Tika 2 reports
+// the namespaced keys, such as meta:last-author, for both OOXML and OLE2;
+// earlier versions report names such as Last-Author.
+func (m Metadata) OfficeMetadata() OfficeMetadata {
+	o := OfficeMetadata{
+		Application:    m.first("extended-properties:Application", "Application-Name"),
+		Author:         m.first(MetaCreator, "meta:author", "Author"),
+		LastModifiedBy: m.first("meta:last-author", "Last-Author"),
+		Revision:       int(m.firstInt("cp:revision", "Revision-Number")),
+		Company:        m.first("extended-properties:Company", "Company"),
+		Manager:        m.first("extended-properties:Manager", "Manager"),
+		Template:       m.first("extended-properties:Template", "Template"),
+		Created:        m.firstDate(MetaCreated, "meta:creation-date", "Creation-Date"),
+		Modified:       m.firstDate(MetaModified, "meta:save-date", "Last-Save-Date"),
+		WordCount:      int(m.firstInt("meta:word-count", "Word-Count")),
+		CharacterCount: int(m.firstInt("meta:character-count", "Character Count")),
+		PageCount:      int(m.firstInt("meta:page-count", MetaPageCount, "Page-Count")),
+		SlideCount:     int(m.firstInt("meta:slide-count", "Slide-Count")),
+	}
+	// Office records the total editing time in minutes.
+	if mins, ok := m.firstFloat("extended-properties:TotalTime", "Total-Time"); ok {
+		o.EditTime = time.Duration(mins * float64(time.Minute))
+	}
+	return o
+}
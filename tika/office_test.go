@@ -0,0 +1,91 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOfficeMetadata(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		m    Metadata
+		want OfficeMetadata
+	}{
+		{
+			name: "empty",
+			m:    Metadata{},
+			want: OfficeMetadata{},
+		},
+		{
+			name: "docx",
+			m: Metadata{
+				"extended-properties:Application": {"Microsoft Office Word"},
+				"dc:creator":                      {"Ann"},
+				"meta:last-author":                {"Bob"},
+				"cp:revision":                     {"7"},
+				"extended-properties:Company":     {"ACME"},
+				"extended-properties:Template":    {"Normal.dotm"},
+				"extended-properties:TotalTime":   {"90"},
+				"dcterms:created":                 {"2020-01-02T03:04:05Z"},
+				"meta:word-count":                 {"1234"},
+				"meta:character-count":            {"5678"},
+				"meta:page-count":                 {"5"},
+			},
+			want: OfficeMetadata{
+				Application:    "Microsoft Office Word",
+				Author:         "Ann",
+				LastModifiedBy: "Bob",
+				Revision:       7,
+				Company:        "ACME",
+				Template:       "Normal.dotm",
+				EditTime:       90 * time.Minute,
+				Created:        created,
+				WordCount:      1234,
+				CharacterCount: 5678,
+				PageCount:      5,
+			},
+		},
+		{
+			name: "legacy ppt",
+			m: Metadata{
+				"Application-Name": {"Microsoft PowerPoint"},
+				"Author":           {"Ann"},
+				"Last-Author":      {"Bob"},
+				"Revision-Number":  {"3"},
+				"Creation-Date":    {"2020-01-02T03:04:05Z"},
+				"Slide-Count":      {"20"},
+			},
+			want: OfficeMetadata{
+				Application:    "Microsoft PowerPoint",
+				Author:         "Ann",
+				LastModifiedBy: "Bob",
+				Revision:       3,
+				Created:        created,
+				SlideCount:     20,
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := test.m.OfficeMetadata(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: OfficeMetadata() got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// A ClientOption configures a Client. See NewClientWithOptions.
+type ClientOption func(*clientConfig)
+
+type clientConfig struct {
+	httpClient     *http.Client
+	timeout        time.Duration
+	header         http.Header
+	retry          *RetryPolicy
+	breaker        *breaker
+	sem            semaphore
+	limiter        *limiter
+	defaults       []RequestOption
+	compress       bool
+	middleware     []Middleware
+	tracer         Tracer
+	metrics        Metrics
+	logger         *slog.Logger
+	cache          Cache
+	noDeadline     bool
+	maxUpload      int64
+	truncateUpload bool
+	normalize      *TextNormalization
+	// failover, if not nil, sends requests to several servers.
+	failover         *failover
+	balance          bool
+	failoverCooldown time.Duration
+	// transport modifies the http.Transport of the client.
+	transport []func(*http.Transport)
+}
+
+// WithHTTPClient sets the *http.Client used to call the Tika Server. The
+// default is http.DefaultClient.
+func WithHTTPClient(c *http.Client) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.httpClient = c
+	}
+}
+
+// WithTimeout sets a time limit for each request, including reading the
+// response body. The *http.Client passed to WithHTTPClient is not modified.
+func WithTimeout(d time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.timeout = d
+	}
+}
+
+// WithDefaultHeader adds a header that is sent with every request. Headers
+// set by individual requests take precedence.
+func WithDefaultHeader(key, value string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.header.Add(key, value)
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.header.Set("User-Agent", ua)
+	}
+}
+
+// WithRetry retries requests that fail with a connection error or a 502, 503
+// or 504 response according to p. Only idempotent requests (GET, HEAD and
+// PUT) whose body can be sent again are retried. For example:
+//
+//	c := tika.NewClientWithOptions(url, tika.WithRetry(tika.RetryPolicy{
+//		MaxAttempts: 5,
+//		MaxElapsed:  time.Minute,
+//	}))
+func WithRetry(p RetryPolicy) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.retry = &p
+	}
+}
+
+// WithCircuitBreaker stops sending requests after threshold consecutive
+// failures (connection errors, timeouts and 5xx responses), failing them with
+// ErrCircuitOpen instead. After cooldown, a single request is let through to
+// probe the server: if it succeeds, requests are sent normally again.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		if threshold < 1 {
+			threshold = 1
+		}
+		cfg.breaker = &breaker{threshold: threshold, cooldown: cooldown}
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of requests the Client has in
+// flight at once to n. Further requests wait until a request finishes or
+// their context is done. A request is in flight until its response body has
+// been read.
+func WithMaxConcurrentRequests(n int) ClientOption {
+	return func(cfg *clientConfig) {
+		if n > 0 {
+			cfg.sem = make(semaphore, n)
+		}
+	}
+}
+
+// WithRateLimit limits the Client to perSecond requests per second on
+// average, allowing bursts of up to burst requests. Further requests wait
+// until they are allowed or their context is done.
+func WithRateLimit(perSecond float64, burst int) ClientOption {
+	return func(cfg *clientConfig) {
+		if perSecond > 0 {
+			cfg.limiter = newLimiter(perSecond, burst)
+		}
+	}
+}
+
+// WithRequestDefaults applies opts to every request, before the request's
+// own RequestOptions. For example, to skip embedded documents unless a
+// request asks for them:
+//
+//	c := tika.NewClientWithOptions(url, tika.WithRequestDefaults(tika.WithSkipEmbedded(true)))
+//	c.Parse(ctx, f, tika.WithSkipEmbedded(false))
+func WithRequestDefaults(opts ...RequestOption) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.defaults = append(cfg.defaults, opts...)
+	}
+}
+
+// WithCompression compresses request bodies with gzip, unless they are known
+// to be smaller than 1KB, and asks the server to compress responses.
+// Extracted text compresses well, so this saves a lot of time with remote
+// servers. The server must accept gzip encoded requests, as Tika Server does.
+func WithCompression() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.compress = true
+	}
+}
+
+// WithUnixSocket connects to the Tika Server (or a proxy in front of it)
+// through the Unix domain socket at path instead of TCP. The host of the
+// Client's URL is only used for the Host header. If WithHTTPClient is also
+// used, its Transport must be nil or an *http.Transport, which is copied
+// rather than modified. For example:
+//
+//	c := tika.NewClientWithOptions("http://tika", tika.WithUnixSocket("/run/tika.sock"))
+func WithUnixSocket(path string) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.transport = append(cfg.transport, func(t *http.Transport) {
+			var d net.Dialer
+			t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return d.DialContext(ctx, "unix", path)
+			}
+		})
+	}
+}
+
+// WithMaxIdleConnsPerHost sets how many idle connections to each server are
+// kept open for reuse. The Go default of 2 makes concurrent requests open and
+// close connections constantly, so set it to about the number of concurrent
+// requests. Like WithUnixSocket, it requires an *http.Transport.
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.transport = append(cfg.transport, func(t *http.Transport) {
+			t.MaxIdleConnsPerHost = n
+			if t.MaxIdleConns != 0 && t.MaxIdleConns < n {
+				t.MaxIdleConns = n
+			}
+		})
+	}
+}
+
+// WithIdleConnTimeout sets how long idle connections are kept open. Like
+// WithUnixSocket, it requires an *http.Transport.
+func WithIdleConnTimeout(d time.Duration) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.transport = append(cfg.transport, func(t *http.Transport) {
+			t.IdleConnTimeout = d
+		})
+	}
+}
+
+// WithHTTP2 sets whether HTTP/2 is used with https servers that support it,
+// which it is by default. Like WithUnixSocket, it requires an
+// *http.Transport.
+func WithHTTP2(enabled bool) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.transport = append(cfg.transport, func(t *http.Transport) {
+			t.ForceAttemptHTTP2 = enabled
+			if !enabled {
+				// A non-nil, empty map disables HTTP/2.
+				t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+			}
+		})
+	}
+}
+
+// NewClientWithOptions creates a new Client for the Tika Server at urlString,
+// configured by opts.
+func NewClientWithOptions(urlString string, opts ...ClientOption) *Client {
+	cfg := &clientConfig{header: make(http.Header)}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	hc := cfg.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	if cfg.timeout > 0 || len(cfg.transport) > 0 {
+		copied := *hc
+		if cfg.timeout > 0 {
+			copied.Timeout = cfg.timeout
+		}
+		if len(cfg.transport) > 0 {
+			copied.Transport = modifyTransport(hc.Transport, cfg.transport)
+		}
+		hc = &copied
+	}
+	if f := cfg.failover; f != nil {
+		copied := *hc
+		f.base = copied.Transport
+		if f.base == nil {
+			f.base = http.DefaultTransport
+		}
+		f.balance = cfg.balance
+		if cfg.failoverCooldown > 0 {
+			f.cooldown = cfg.failoverCooldown
+		}
+		copied.Transport = f
+		hc = &copied
+	}
+	if cfg.breaker != nil && cfg.metrics != nil {
+		m := cfg.metrics
+		cfg.breaker.onChange = func(open bool) { m.CircuitBreakerChanged(open) }
+	}
+	var cache *responseCache
+	if cfg.cache != nil {
+		cache = &responseCache{c: cfg.cache}
+	}
+	return &Client{
+		url:            urlString,
+		httpClient:     hc,
+		header:         cfg.header,
+		retry:          cfg.retry,
+		breaker:        cfg.breaker,
+		sem:            cfg.sem,
+		limiter:        cfg.limiter,
+		defaults:       cfg.defaults,
+		compress:       cfg.compress,
+		middleware:     cfg.middleware,
+		tracer:         cfg.tracer,
+		metrics:        cfg.metrics,
+		logger:         cfg.logger,
+		cache:          cache,
+		noDeadline:     cfg.noDeadline,
+		maxUpload:      cfg.maxUpload,
+		truncateUpload: cfg.truncateUpload,
+		normalize:      cfg.normalize,
+	}
+}
+
+// modifyTransport returns a copy of rt modified by mods. rt must be nil (for
+// http.DefaultTransport) or an *http.Transport; otherwise it is returned
+// unmodified.
+func modifyTransport(rt http.RoundTripper, mods []func(*http.Transport)) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return rt
+	}
+	t = t.Clone()
+	for _, mod := range mods {
+		mod(t)
+	}
+	return t
+}
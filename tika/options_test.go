@@ -0,0 +1,145 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		fmt.Fprint(w, "test value")
+	}))
+	defer ts.Close()
+
+	hc := &http.Client{}
+	c := NewClientWithOptions(ts.URL,
+		WithHTTPClient(hc),
+		WithTimeout(time.Minute),
+		WithDefaultHeader("X-Test", "one"),
+		WithDefaultHeader("X-Test", "two"),
+		WithDefaultHeader("Accept", "text/plain"),
+		WithUserAgent("go-tika-test"))
+	if hc.Timeout != 0 {
+		t.Errorf("WithTimeout modified the *http.Client passed to WithHTTPClient")
+	}
+	if c.httpClient.Timeout != time.Minute {
+		t.Errorf("NewClientWithOptions timeout = %v, want %v", c.httpClient.Timeout, time.Minute)
+	}
+
+	if _, err := c.Parse(context.Background(), nil); err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if ua := got.Get("User-Agent"); ua != "go-tika-test" {
+		t.Errorf("User-Agent = %q, want %q", ua, "go-tika-test")
+	}
+	if v := got["X-Test"]; len(v) != 2 || v[0] != "one" || v[1] != "two" {
+		t.Errorf("X-Test = %q, want [one two]", v)
+	}
+
+	// Headers set by a request take precedence over the defaults.
+	if _, err := c.Parsers(context.Background()); err == nil {
+		t.Fatalf("Parsers got no error for a non-JSON response, want an error")
+	}
+	if accept := got.Get("Accept"); accept != "application/json" {
+		t.Errorf("Accept = %q, want %q", accept, "application/json")
+	}
+}
+
+func TestNewClientWithOptionsDefaults(t *testing.T) {
+	c := NewClientWithOptions("http://localhost:9998")
+	if c.httpClient != http.DefaultClient {
+		t.Errorf("NewClientWithOptions httpClient = %v, want http.DefaultClient", c.httpClient)
+	}
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tika")
+	if err != nil {
+		t.Fatalf("error creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tika.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skipf("Unix sockets are not supported: %v", err)
+	}
+	want := "Apache Tika 1.21"
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, want)
+	})}
+	go srv.Serve(l)
+	defer srv.Close()
+
+	hc := &http.Client{Timeout: time.Minute}
+	c := NewClientWithOptions("http://tika", WithHTTPClient(hc), WithUnixSocket(path))
+	got, err := c.Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version got error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Version got %q, want %q", got, want)
+	}
+	if hc.Transport != nil {
+		t.Errorf("WithUnixSocket modified the Transport of the *http.Client")
+	}
+	if c.httpClient.Timeout != time.Minute {
+		t.Errorf("WithUnixSocket changed the Timeout to %v, want %v", c.httpClient.Timeout, time.Minute)
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	c := NewClientWithOptions("http://localhost:9998",
+		WithMaxIdleConnsPerHost(200),
+		WithIdleConnTimeout(time.Minute),
+		WithHTTP2(false),
+	)
+	tr, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("Transport got %T, want *http.Transport", c.httpClient.Transport)
+	}
+	if tr == http.DefaultTransport {
+		t.Fatalf("transport options modified http.DefaultTransport")
+	}
+	if tr.MaxIdleConnsPerHost != 200 || tr.MaxIdleConns < 200 {
+		t.Errorf("MaxIdleConnsPerHost = %d and MaxIdleConns = %d, want 200 and at least 200", tr.MaxIdleConnsPerHost, tr.MaxIdleConns)
+	}
+	if tr.IdleConnTimeout != time.Minute {
+		t.Errorf("IdleConnTimeout = %v, want %v", tr.IdleConnTimeout, time.Minute)
+	}
+	if tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil {
+		t.Errorf("ForceAttemptHTTP2 = %v and TLSNextProto = %v, want HTTP/2 disabled", tr.ForceAttemptHTTP2, tr.TLSNextProto)
+	}
+
+	// Transports of other types are left alone.
+	rt := roundTripperFunc(func(*http.Request) (*http.Response, error) { return nil, fmt.Errorf("unused") })
+	c = NewClientWithOptions("http://localhost:9998", WithHTTPClient(&http.Client{Transport: rt}), WithIdleConnTimeout(time.Minute))
+	if _, ok := c.httpClient.Transport.(roundTripperFunc); !ok {
+		t.Errorf("Transport got %T, want the roundTripperFunc", c.httpClient.Transport)
+	}
+}
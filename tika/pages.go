@@ -0,0 +1,134 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// A Page is a page of a paginated document, such as a PDF, or a slide of a
+// presentation.
+type Page struct {
+	// Number is the number of the page, starting at 1.
+	Number int
+	Text   string
+}
+
+// pageClasses are the classes of the elements Tika wraps each page in: "page"
+// for PDFs and "slide-content" for presentations.
+var pageClasses = []string{"page", "slide-content"}
+
+// blockElements end a line of page text.
+var blockElements = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// ParsePages parses the given input and returns the text of each of its pages,
+// in order. Documents that aren't paginated have no pages. If the error is not
+// nil, the result is undefined.
+func (c *Client) ParsePages(ctx context.Context, input io.Reader, opts ...RequestOption) ([]Page, error) {
+	body, err := c.call(ctx, input, "PUT", "/tika", xhtmlHeader, opts)
+	if err != nil {
+		return nil, err
+	}
+	return splitPages(bytes.NewReader(body))
+}
+
+// splitPages returns the pages of the XHTML document in r.
+func splitPages(r io.Reader) ([]Page, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	var (
+		pages  []Page
+		text   strings.Builder
+		depth  int // Depth of the element being read within the page.
+		inPage bool
+	)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return pages, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if inPage {
+				depth++
+				continue
+			}
+			if isPage(t) {
+				inPage, depth = true, 0
+				text.Reset()
+			}
+		case xml.CharData:
+			if inPage {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if !inPage {
+				continue
+			}
+			if depth > 0 {
+				depth--
+				if blockElements[strings.ToLower(t.Name.Local)] {
+					text.WriteByte('\n')
+				}
+				continue
+			}
+			inPage = false
+			pages = append(pages, Page{Number: len(pages) + 1, Text: pageText(text.String())})
+		}
+	}
+}
+
+// isPage reports whether e wraps a page.
+func isPage(e xml.StartElement) bool {
+	for _, a := range e.Attr {
+		if a.Name.Local != "class" {
+			continue
+		}
+		for _, c := range strings.Fields(a.Value) {
+			for _, p := range pageClasses {
+				if c == p {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// pageText trims the lines of s and drops the empty ones.
+func pageText(s string) string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParsePages(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Page
+	}{
+		{
+			name: "pdf",
+			body: `<html xmlns="http://www.w3.org/1999/xhtml"><head><title>T</title></head><body>
+<div class="page"><p>First
+page</p>
+<div class="annotation"><p>Note</p></div></div>
+<div class="page"><p></p></div>
+<div class="page"><h1>Third</h1><p>Last &amp; least</p></div>
+</body></html>`,
+			want: []Page{
+				{Number: 1, Text: "First\npage\nNote"},
+				{Number: 2, Text: ""},
+				{Number: 3, Text: "Third\nLast & least"},
+			},
+		},
+		{
+			name: "presentation",
+			body: `<html><body><div class="slide-content"><p>One</p></div><div class="slide-content"><p>Two</p></div></body></html>`,
+			want: []Page{{Number: 1, Text: "One"}, {Number: 2, Text: "Two"}},
+		},
+		{
+			name: "not paginated",
+			body: `<html><body><p>Text</p></body></html>`,
+		},
+	}
+	for _, test := range tests {
+		var accept string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accept = r.Header.Get("Accept")
+			fmt.Fprint(w, test.body)
+		}))
+		got, err := NewClient(nil, ts.URL).ParsePages(context.Background(), nil)
+		ts.Close()
+		if err != nil {
+			t.Errorf("%s: ParsePages got error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: ParsePages got %q, want %q", test.name, got, test.want)
+		}
+		if accept != "text/html" {
+			t.Errorf("%s: ParsePages requested Accept %q, want %q", test.name, accept, "text/html")
+		}
+	}
+	if _, err := errorClient.ParsePages(context.Background(), nil); err == nil {
+		t.Errorf("ParsePages got no error, want an error")
+	}
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import "strconv"
+
+// pdfOption returns a RequestOption that sets the PDF parser property name.
+func pdfOption(name string, v bool) RequestOption {
+	return setHeader("X-Tika-PDF"+name, strconv.FormatBool(v))
+}
+
+// WithPDFSortByPosition sorts extracted text by its position on the page
+// rather than the order it appears in the file. This is often needed for
+// tables and multi-column layouts, such as financial statements.
+func WithPDFSortByPosition(v bool) RequestOption {
+	return pdfOption("sortByPosition", v)
+}
+
+// WithPDFExtractInlineImages extracts images embedded in PDF pages as
+// embedded documents, so they can be parsed (and OCRed) too.
+func WithPDFExtractInlineImages(v bool) RequestOption {
+	return pdfOption("extractInlineImages", v)
+}
+
+// WithPDFExtractUniqueInlineImagesOnly only extracts the first occurrence of
+// an inline image that is used more than once, such as a logo on every page.
+// It only applies if WithPDFExtractInlineImages is enabled.
+func WithPDFExtractUniqueInlineImagesOnly(v bool) RequestOption {
+	return pdfOption("extractUniqueInlineImagesOnly", v)
+}
+
+// WithPDFExtractAnnotationText extracts the text of annotations, such as
+// comments and links.
+func WithPDFExtractAnnotationText(v bool) RequestOption {
+	return pdfOption("extractAnnotationText", v)
+}
+
+// WithPDFExtractAcroFormContent extracts the content of interactive form
+// fields.
+func WithPDFExtractAcroFormContent(v bool) RequestOption {
+	return pdfOption("extractAcroFormContent", v)
+}
+
+// WithPDFEnableAutoSpace inserts spaces between words when the PDF doesn't
+// contain them explicitly.
+func WithPDFEnableAutoSpace(v bool) RequestOption {
+	return pdfOption("enableAutoSpace", v)
+}
+
+// WithPDFSuppressDuplicateOverlappingText removes text that is drawn more
+// than once at the same position, for example to make it look bold.
+func WithPDFSuppressDuplicateOverlappingText(v bool) RequestOption {
+	return pdfOption("suppressDuplicateOverlappingText", v)
+}
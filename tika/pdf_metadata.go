@@ -0,0 +1,90 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PDFMetadata is the metadata Tika's PDFBox-based parser reports for a PDF.
+// Fields Tika doesn't report are zero.
+type PDFMetadata struct {
+	PageCount int
+	// Producer is the software that wrote the PDF, and CreatorTool the
+	// software that created the original document.
+	Producer    string
+	CreatorTool string
+	// Version is the PDF version, such as "1.7".
+	Version  string
+	Created  time.Time
+	Modified time.Time
+	// Encrypted reports whether the PDF is encrypted. Permissions are the
+	// access permissions it grants.
+	Encrypted   bool
+	Permissions PDFPermissions
+	// HasXFA reports whether the PDF has an XFA form, and HasAcroForm
+	// whether it has AcroForm fields.
+	HasXFA      bool
+	HasAcroForm bool
+}
+
+// PDFPermissions are the access permissions of a PDF. A PDF without
+// permissions, such as one that isn't encrypted, grants all of them.
+type PDFPermissions struct {
+	Print                   bool
+	PrintDegraded           bool
+	Modify                  bool
+	ModifyAnnotations       bool
+	FillInForm              bool
+	AssembleDocument        bool
+	ExtractContent          bool
+	ExtractForAccessibility bool
+}
+
+// PDFMetadata returns the PDF metadata of the document.
+func (m Metadata) PDFMetadata() PDFMetadata {
+	return PDFMetadata{
+		PageCount:   int(m.firstInt(MetaPageCount, "meta:page-count")),
+		Producer:    m.first("pdf:producer", "producer"),
+		CreatorTool: m.first("xmp:CreatorTool", "pdf:docinfo:creator_tool"),
+		Version:     m.first("pdf:PDFVersion", "pdf:PDFExtensionVersion"),
+		Created:     m.firstDate(MetaCreated, "pdf:docinfo:created", "meta:creation-date", "Creation-Date"),
+		Modified:    m.firstDate(MetaModified, "pdf:docinfo:modified", "Last-Modified"),
+		Encrypted:   m.firstBool("pdf:encrypted"),
+		Permissions: PDFPermissions{
+			Print:                   m.permission("access_permission:can_print"),
+			PrintDegraded:           m.permission("access_permission:can_print_degraded"),
+			Modify:                  m.permission("access_permission:can_modify"),
+			ModifyAnnotations:       m.permission("access_permission:modify_annotations"),
+			FillInForm:              m.permission("access_permission:fill_in_form"),
+			AssembleDocument:        m.permission("access_permission:assemble_document"),
+			ExtractContent:          m.permission("access_permission:extract_content"),
+			ExtractForAccessibility: m.permission("access_permission:extract_for_accessibility"),
+		},
+		HasXFA:      m.firstBool("pdf:hasXFA"),
+		HasAcroForm: m.firstBool("pdf:hasAcroFormFields"),
+	}
+}
+
+// permission reports whether the PDF access permission key is granted, which
+// it is unless Tika reports otherwise.
+func (m Metadata) permission(key string) bool {
+	b, err := strconv.ParseBool(strings.TrimSpace(m.Get(key)))
+	return err != nil || b
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPDFMetadata(t *testing.T) {
+	all := PDFPermissions{true, true, true, true, true, true, true, true}
+	tests := []struct {
+		name string
+		m    Metadata
+		want PDFMetadata
+	}{
+		{
+			name: "empty",
+			m:    Metadata{},
+			want: PDFMetadata{Permissions: all},
+		},
+		{
+			name: "pdfbox keys",
+			m: Metadata{
+				"xmpTPg:NPages":                     {"12"},
+				"pdf:producer":                      {"Skia/PDF"},
+				"xmp:CreatorTool":                   {"Chromium"},
+				"pdf:PDFVersion":                    {"1.4"},
+				"dcterms:created":                   {"2020-01-02T03:04:05Z"},
+				"pdf:docinfo:modified":              {"2021-06-07T00:00:00Z"},
+				"pdf:encrypted":                     {"true"},
+				"access_permission:can_print":       {"true"},
+				"access_permission:can_modify":      {"false"},
+				"access_permission:extract_content": {"false"},
+				"pdf:hasXFA":                        {"false"},
+				"pdf:hasAcroFormFields":             {"true"},
+			},
+			want: PDFMetadata{
+				PageCount:   12,
+				Producer:    "Skia/PDF",
+				CreatorTool: "Chromium",
+				Version:     "1.4",
+				Created:     time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+				Modified:    time.Date(2021, 6, 7, 0, 0, 0, 0, time.UTC),
+				Encrypted:   true,
+				Permissions: PDFPermissions{
+					Print:                   true,
+					PrintDegraded:           true,
+					ModifyAnnotations:       true,
+					FillInForm:              true,
+					AssembleDocument:        true,
+					ExtractForAccessibility: true,
+				},
+				HasAcroForm: true,
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := test.m.PDFMetadata(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: PDFMetadata() got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
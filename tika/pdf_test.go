@@ -0,0 +1,47 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestPDFOptions(t *testing.T) {
+	cfg := newRequestConfig(nil, []RequestOption{
+		WithPDFSortByPosition(true),
+		WithPDFExtractInlineImages(true),
+		WithPDFExtractUniqueInlineImagesOnly(false),
+		WithPDFExtractAnnotationText(false),
+		WithPDFExtractAcroFormContent(true),
+		WithPDFEnableAutoSpace(true),
+		WithPDFSuppressDuplicateOverlappingText(true),
+	})
+	want := http.Header{
+		"X-Tika-Pdfsortbyposition":                   {"true"},
+		"X-Tika-Pdfextractinlineimages":              {"true"},
+		"X-Tika-Pdfextractuniqueinlineimagesonly":    {"false"},
+		"X-Tika-Pdfextractannotationtext":            {"false"},
+		"X-Tika-Pdfextractacroformcontent":           {"true"},
+		"X-Tika-Pdfenableautospace":                  {"true"},
+		"X-Tika-Pdfsuppressduplicateoverlappingtext": {"true"},
+	}
+	if !reflect.DeepEqual(cfg.header, want) {
+		t.Errorf("PDF options got header %v, want %v", cfg.header, want)
+	}
+}
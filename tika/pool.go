@@ -0,0 +1,240 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// A ServerPool runs several Tika Servers and balances requests between them.
+// Create a ServerPool with NewServerPool, start it with Start, get a Client
+// that uses every server in the pool with Client, and shut the servers down
+// with Stop.
+type ServerPool struct {
+	runners        []Runner
+	bal            *balancer
+	transport      http.RoundTripper
+	healthInterval time.Duration
+
+	checkers []*HealthChecker
+
+	mu       sync.Mutex
+	onChange []func(url string, status HealthStatus, err error)
+}
+
+// A PoolOption configures a ServerPool. See NewServerPool.
+type PoolOption func(*ServerPool)
+
+// WithHealthCheckInterval sets how often the ServerPool checks whether each
+// server is responding. Requests are only sent to servers that passed their
+// latest check, unless none did. The default is 5 seconds.
+func WithHealthCheckInterval(d time.Duration) PoolOption {
+	return func(p *ServerPool) {
+		p.healthInterval = d
+	}
+}
+
+// WithPoolTransport sets the http.RoundTripper used to send requests and
+// health checks to the servers, for example one whose TLS configuration trusts
+// the servers' certificates. The default is http.DefaultTransport.
+func WithPoolTransport(rt http.RoundTripper) PoolOption {
+	return func(p *ServerPool) {
+		p.transport = rt
+	}
+}
+
+// NewServerPool creates a new ServerPool of runners, which must not be
+// started. Each runner must listen on a different URL. For example, to run
+// two local servers:
+//
+//	s1, err := tika.NewServer("tika-server.jar", "9998")
+//	...
+//	s2, err := tika.NewServer("tika-server.jar", "9999")
+//	...
+//	p, err := tika.NewServerPool([]tika.Runner{s1, s2})
+func NewServerPool(runners []Runner, opts ...PoolOption) (*ServerPool, error) {
+	if len(runners) == 0 {
+		return nil, fmt.Errorf("no servers specified")
+	}
+	p := &ServerPool{
+		runners:        runners,
+		healthInterval: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	var urls []string
+	for _, r := range runners {
+		urls = append(urls, r.URL())
+	}
+	bal, err := newBalancer(urls, p.transport)
+	if err != nil {
+		return nil, err
+	}
+	p.bal = bal
+	return p, nil
+}
+
+// Start starts every server in the pool and waits for them to be available or
+// until ctx is cancelled. If any server fails to start, the servers that did
+// start are stopped. The caller must call Stop when finished with the pool.
+func (p *ServerPool) Start(ctx context.Context) error {
+	for i, r := range p.runners {
+		if err := r.Start(ctx); err != nil {
+			for _, started := range p.runners[:i] {
+				started.Stop()
+			}
+			return fmt.Errorf("error starting %s: %v", r.URL(), err)
+		}
+	}
+	p.checkers = nil
+	for _, e := range p.bal.endpoints {
+		e := e
+		h := NewHealthChecker(NewClient(&http.Client{Transport: p.transport}, e.url.String()), p.healthInterval)
+		h.OnChange(func(status HealthStatus, err error) {
+			p.bal.setHealthy(e, status != Unhealthy)
+			p.mu.Lock()
+			subs := p.onChange
+			p.mu.Unlock()
+			for _, f := range subs {
+				f(e.url.String(), status, err)
+			}
+		})
+		p.checkers = append(p.checkers, h)
+		h.Start()
+	}
+	return nil
+}
+
+// OnHealthChange calls f whenever a server in the pool becomes healthy or
+// unhealthy, with its URL. See HealthChecker.OnChange.
+func (p *ServerPool) OnHealthChange(f func(url string, status HealthStatus, err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = append(p.onChange, f)
+}
+
+// Stop stops the health checks and every server in the pool. Stop returns
+// the first error encountered, but always tries to stop every server.
+func (p *ServerPool) Stop() error {
+	for _, h := range p.checkers {
+		h.Stop()
+	}
+	p.checkers = nil
+	var firstErr error
+	for _, r := range p.runners {
+		if err := r.Stop(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("error stopping %s: %v", r.URL(), err)
+		}
+	}
+	return firstErr
+}
+
+// Client returns a Client that sends requests to the servers in the pool in
+// round-robin order, skipping servers that failed their latest health check.
+func (p *ServerPool) Client() *Client {
+	return NewClient(&http.Client{Transport: p.bal}, p.runners[0].URL())
+}
+
+// HealthyURLs returns the URLs of the servers that passed their latest
+// health check.
+func (p *ServerPool) HealthyURLs() []string {
+	return p.bal.healthyURLs()
+}
+
+// A balancer is an http.RoundTripper that sends each request to the next
+// healthy endpoint, in round-robin order.
+type balancer struct {
+	base      http.RoundTripper
+	endpoints []*endpoint
+
+	mu   sync.Mutex
+	next int
+}
+
+// An endpoint is a server a balancer sends requests to.
+type endpoint struct {
+	url     *url.URL
+	healthy bool // Guarded by balancer.mu.
+}
+
+// newBalancer creates a balancer for urls that sends requests using base. If
+// base is nil, http.DefaultTransport is used.
+func newBalancer(urls []string, base http.RoundTripper) (*balancer, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	b := &balancer{base: base}
+	for _, s := range urls {
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL %q: %v", s, err)
+		}
+		b.endpoints = append(b.endpoints, &endpoint{url: u, healthy: true})
+	}
+	return b, nil
+}
+
+// pick returns the next healthy endpoint. If no endpoint is healthy, pick
+// returns the next endpoint regardless.
+func (b *balancer) pick() *endpoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i := 0; i < len(b.endpoints); i++ {
+		e := b.endpoints[(b.next+i)%len(b.endpoints)]
+		if e.healthy {
+			b.next = (b.next + i + 1) % len(b.endpoints)
+			return e
+		}
+	}
+	e := b.endpoints[b.next]
+	b.next = (b.next + 1) % len(b.endpoints)
+	return e
+}
+
+func (b *balancer) setHealthy(e *endpoint, healthy bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e.healthy = healthy
+}
+
+func (b *balancer) healthyURLs() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var urls []string
+	for _, e := range b.endpoints {
+		if e.healthy {
+			urls = append(urls, e.url.String())
+		}
+	}
+	return urls
+}
+
+// RoundTrip implements http.RoundTripper by sending req to the next endpoint.
+func (b *balancer) RoundTrip(req *http.Request) (*http.Response, error) {
+	e := b.pick()
+	r := req.Clone(req.Context())
+	r.URL.Scheme = e.url.Scheme
+	r.URL.Host = e.url.Host
+	r.Host = e.url.Host
+	return b.base.RoundTrip(r)
+}
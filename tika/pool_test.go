@@ -0,0 +1,169 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRunner is a Runner for an already running test server.
+type fakeRunner struct {
+	url              string
+	startErr         error
+	started, stopped int
+}
+
+func (r *fakeRunner) Start(context.Context) error {
+	r.started++
+	return r.startErr
+}
+
+func (r *fakeRunner) Stop() error {
+	r.stopped++
+	return nil
+}
+
+func (r *fakeRunner) URL() string { return r.url }
+
+// countingServer responds with name and counts the requests it receives. It
+// responds with http.StatusInternalServerError while *fail is non-zero.
+func countingServer(name string, hits, fail *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.LoadInt32(fail) != 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		atomic.AddInt32(hits, 1)
+		fmt.Fprint(w, name)
+	}))
+}
+
+func TestServerPool(t *testing.T) {
+	var hits1, hits2, fail1, fail2 int32
+	ts1 := countingServer("one", &hits1, &fail1)
+	defer ts1.Close()
+	ts2 := countingServer("two", &hits2, &fail2)
+	defer ts2.Close()
+	r1, r2 := &fakeRunner{url: ts1.URL}, &fakeRunner{url: ts2.URL}
+
+	p, err := NewServerPool([]Runner{r1, r2}, WithHealthCheckInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewServerPool got error: %v", err)
+	}
+	var mu sync.Mutex
+	var unhealthy []string
+	p.OnHealthChange(func(url string, status HealthStatus, err error) {
+		if status == Unhealthy {
+			mu.Lock()
+			unhealthy = append(unhealthy, url)
+			mu.Unlock()
+		}
+	})
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	c := p.Client()
+	var got []string
+	for i := 0; i < 4; i++ {
+		v, err := c.Version(context.Background())
+		if err != nil {
+			t.Fatalf("Version got error: %v", err)
+		}
+		got = append(got, v)
+	}
+	if want := []string{"one", "two", "one", "two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Version responses = %q, want %q", got, want)
+	}
+
+	atomic.StoreInt32(&fail1, 1)
+	deadline := time.Now().Add(5 * time.Second)
+	for !reflect.DeepEqual(p.HealthyURLs(), []string{ts2.URL}) {
+		if time.Now().After(deadline) {
+			t.Fatalf("HealthyURLs() = %q, want %q", p.HealthyURLs(), []string{ts2.URL})
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := c.Version(context.Background()); err != nil || v != "two" {
+			t.Errorf("Version with an unhealthy server got (%q, %v), want (%q, nil)", v, err, "two")
+		}
+	}
+
+	if err := p.Stop(); err != nil {
+		t.Errorf("Stop got error: %v", err)
+	}
+	mu.Lock()
+	if want := []string{ts1.URL}; !reflect.DeepEqual(unhealthy, want) {
+		t.Errorf("OnHealthChange reported %q unhealthy, want %q", unhealthy, want)
+	}
+	mu.Unlock()
+	for _, r := range []*fakeRunner{r1, r2} {
+		if r.started != 1 || r.stopped != 1 {
+			t.Errorf("runner %s started %d and stopped %d times, want 1 and 1", r.url, r.started, r.stopped)
+		}
+	}
+}
+
+func TestServerPoolStartError(t *testing.T) {
+	r1 := &fakeRunner{url: "http://localhost:9998"}
+	r2 := &fakeRunner{url: "http://localhost:9999", startErr: fmt.Errorf("no java")}
+	p, err := NewServerPool([]Runner{r1, r2})
+	if err != nil {
+		t.Fatalf("NewServerPool got error: %v", err)
+	}
+	if err := p.Start(context.Background()); err == nil {
+		t.Fatalf("Start got no error, want an error")
+	}
+	if r1.stopped != 1 {
+		t.Errorf("started runner stopped %d times, want 1", r1.stopped)
+	}
+	if _, err := NewServerPool(nil); err == nil {
+		t.Errorf("NewServerPool(nil) got no error, want an error")
+	}
+}
+
+func TestServerPoolTransport(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "tls")
+	}))
+	defer ts.Close()
+
+	p, err := NewServerPool([]Runner{&fakeRunner{url: ts.URL}}, WithPoolTransport(ts.Client().Transport), WithHealthCheckInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewServerPool got error: %v", err)
+	}
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	defer p.Stop()
+	if v, err := p.Client().Version(context.Background()); err != nil || v != "tls" {
+		t.Errorf("Version got (%q, %v), want (%q, nil)", v, err, "tls")
+	}
+	// The health checks trust the test certificate too.
+	time.Sleep(50 * time.Millisecond)
+	if got, want := p.HealthyURLs(), []string{ts.URL}; !reflect.DeepEqual(got, want) {
+		t.Errorf("HealthyURLs() = %q, want %q", got, want)
+	}
+}
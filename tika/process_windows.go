@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// terminateProcess always fails: Windows has no SIGTERM, so p can only be
+// killed.
+func terminateProcess(p *os.Process) error {
+	return errors.New("terminating a process is not supported on Windows")
+}
+
+// killProcess kills p and every process it started, such as the child JVM of
+// a server started WithSpawnChild. Windows doesn't kill the children of a
+// process with it, so taskkill is used to kill the whole tree, falling back
+// to killing just p.
+func killProcess(p *os.Process) error {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(p.Pid)).Run(); err != nil {
+		return p.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"io"
+	"net/http"
+)
+
+// WithUploadProgress calls f as the document is sent with the number of bytes
+// sent so far and the size of the document, or -1 if the size is not known.
+// Sizes are those of the document before WithCompression compresses it. If
+// the request is retried, sent starts again at 0. f is called from the
+// goroutine sending the request, so it must not block for long.
+func WithUploadProgress(f func(sent, total int64)) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.uploadProgress = f
+	}
+}
+
+// WithResponseProgress calls f as the response is read with the number of
+// bytes read so far and the size of the response, or -1 if the size is not
+// known. For methods that return a reader, like ParseReader, f is called as
+// the reader is read. Cached responses are not reported.
+func WithResponseProgress(f func(received, total int64)) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.responseProgress = f
+	}
+}
+
+// trackUpload reports reading the body of req, and of every copy of it made
+// for retries, to f. If total is negative, the ContentLength of req is used
+// if it is known.
+func trackUpload(req *http.Request, total int64, f func(sent, total int64)) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return
+	}
+	if total < 0 && req.ContentLength > 0 {
+		total = req.ContentLength
+	}
+	req.Body = &progressBody{ReadCloser: req.Body, total: total, f: f}
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return &progressBody{ReadCloser: body, total: total, f: f}, nil
+		}
+	}
+}
+
+// trackResponse returns send with the body of successful responses reported
+// to f as it is read.
+func trackResponse(send func(*http.Request) (*http.Response, error), f func(received, total int64)) func(*http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		resp, err := send(req)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = &progressBody{ReadCloser: resp.Body, total: resp.ContentLength, f: f}
+		return resp, nil
+	}
+}
+
+// progressBody is a request or response body that calls f with the number
+// of bytes read so far after every read.
+type progressBody struct {
+	io.ReadCloser
+	n, total int64
+	f        func(n, total int64)
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.n += int64(n)
+		b.f(b.n, b.total)
+	}
+	return n, err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%d", len(b))
+	}))
+	defer ts.Close()
+	doc := strings.Repeat("a", 100000)
+
+	tests := []struct {
+		name      string
+		input     io.Reader
+		opts      []ClientOption
+		wantSent  int64
+		wantTotal int64
+	}{
+		{name: "sized", input: strings.NewReader(doc), wantSent: 100000, wantTotal: 100000},
+		{name: "unsized", input: ioutil.NopCloser(strings.NewReader(doc)), wantSent: 100000, wantTotal: -1},
+		{name: "compressed", input: strings.NewReader(doc), opts: []ClientOption{WithCompression()}, wantSent: 100000, wantTotal: 100000},
+		{name: "truncated", input: strings.NewReader(doc), opts: []ClientOption{WithMaxUploadSize(10), WithUploadTruncation()}, wantSent: 10, wantTotal: 10},
+	}
+	for _, test := range tests {
+		c := NewClientWithOptions(ts.URL, test.opts...)
+		var calls int
+		var sent, total int64
+		progress := WithUploadProgress(func(s, t int64) {
+			calls++
+			sent, total = s, t
+		})
+		if _, err := c.Parse(context.Background(), test.input, progress); err != nil {
+			t.Errorf("Parse(%s) got error: %v", test.name, err)
+			continue
+		}
+		if calls == 0 || sent != test.wantSent || total != test.wantTotal {
+			t.Errorf("Parse(%s) last progress = %d/%d after %d calls, want %d/%d", test.name, sent, total, calls, test.wantSent, test.wantTotal)
+		}
+	}
+}
+
+func TestResponseProgress(t *testing.T) {
+	body := strings.Repeat("b", 5000)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		fmt.Fprint(w, body)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+
+	var received, total int64
+	progress := WithResponseProgress(func(r, t int64) {
+		received, total = r, t
+	})
+	rc, err := c.ParseReader(context.Background(), strings.NewReader("doc"), progress)
+	if err != nil {
+		t.Fatalf("ParseReader got error: %v", err)
+	}
+	defer rc.Close()
+	if received != 0 {
+		t.Errorf("progress before reading = %d, want 0", received)
+	}
+	if _, err := io.Copy(ioutil.Discard, rc); err != nil {
+		t.Fatalf("error reading response: %v", err)
+	}
+	if want := int64(len(body)); received != want || total != want {
+		t.Errorf("progress = %d/%d, want %d/%d", received, total, want, want)
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// A RequestOption configures a single request. Every Client method accepts
+// RequestOptions, for example:
+//
+//	body, err := c.Parse(ctx, f, tika.WithHeader("X-Tika-OCRLanguage", "fra"))
+type RequestOption func(*requestConfig)
+
+type requestConfig struct {
+	header http.Header
+	// noBody is set if the input must not be sent, see WithFileURL.
+	noBody bool
+	// uploadProgress and responseProgress are called as the document is
+	// sent and the response is read, if they are not nil.
+	uploadProgress   func(sent, total int64)
+	responseProgress func(received, total int64)
+	// form is set if the input is sent as a multipart form, see
+	// WithMultipartForm.
+	form bool
+}
+
+// newRequestConfig applies defaults and then opts to a new requestConfig.
+func newRequestConfig(defaults, opts []RequestOption) *requestConfig {
+	cfg := &requestConfig{header: make(http.Header)}
+	for _, opt := range defaults {
+		opt(cfg)
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithHeader adds a header to the request. Most Tika Server configuration is
+// done with X-Tika-* headers; see the Tika Server documentation. Headers set
+// by WithHeader take precedence over the Client's default headers and the
+// headers set by the method itself.
+func WithHeader(key, value string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.header.Add(key, value)
+	}
+}
+
+// setHeader returns a RequestOption that sets a header, replacing any
+// earlier value. Typed options use it so that a request can override the
+// Client's WithRequestDefaults.
+func setHeader(key, value string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.header.Set(key, value)
+	}
+}
+
+// WithHeaders adds all of h to the request, like WithHeader.
+func WithHeaders(h http.Header) RequestOption {
+	return func(cfg *requestConfig) {
+		for k, vs := range h {
+			for _, v := range vs {
+				cfg.header.Add(k, v)
+			}
+		}
+	}
+}
+
+// WithSkipEmbedded skips embedded documents, such as email attachments and
+// the files in an archive, so only the container itself is parsed.
+func WithSkipEmbedded(skip bool) RequestOption {
+	return setHeader("X-Tika-Skip-Embedded", strconv.FormatBool(skip))
+}
+
+// WithMaxEmbeddedResources limits the number of embedded documents returned
+// by MetaRecursive and ParseRecursive to n. Parsing stops once the limit is
+// reached and the container's metadata has the X-TIKA:EXCEPTION:warning
+// field set. A negative n means no limit.
+func WithMaxEmbeddedResources(n int) RequestOption {
+	return setHeader("maxEmbeddedResources", strconv.Itoa(n))
+}
+
+// WithWriteLimit asks the Tika Server to stop extracting text after n
+// characters per document. With MetaRecursive, limited documents have the
+// X-TIKA:Exception:write_limit_reached field set. Servers that don't support
+// write limits ignore it; see ParseLimited to enforce the limit client side.
+func WithWriteLimit(n int) RequestOption {
+	return setHeader("writeLimit", strconv.Itoa(n))
+}
+
+// WithFilename tells the Tika Server the name of the input file, which makes
+// detecting its type more accurate, for example with Detect, Parse and Meta.
+func WithFilename(name string) RequestOption {
+	return setHeader("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+}
+
+// WithFileURL tells the Tika Server to read the input from file instead of
+// the request body, which avoids copying large files when the server runs on
+// the same host. file is a URL or a local path; the input passed to the
+// Client method is not sent, so it may be nil. The server must be started
+// with -enableUnsecureFeatures -enableFileUrl (see WithEnableFileURL), which
+// lets any client read any file the server can.
+func WithFileURL(file string) RequestOption {
+	if u, err := url.Parse(file); err != nil || len(u.Scheme) < 2 {
+		// A path, possibly starting with a Windows drive letter.
+		if abs, err := filepath.Abs(file); err == nil {
+			file = abs
+		}
+		file = fileURL(file)
+	}
+	return func(cfg *requestConfig) {
+		cfg.header.Set("fileUrl", file)
+		cfg.noBody = true
+	}
+}
+
+// fileURL returns the file URL of the absolute path p. Paths starting with a
+// Windows volume name, like C:\x, get a leading slash: file:///C:/x.
+func fileURL(p string) string {
+	p = filepath.ToSlash(p)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Scheme: "file", Path: p}).String()
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRequestHeaders(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithDefaultHeader("X-Tika-OCRLanguage", "eng"))
+
+	tests := []struct {
+		name string
+		call func(opts ...RequestOption) error
+		opts []RequestOption
+		want http.Header
+	}{
+		{
+			name: "no options",
+			call: func(opts ...RequestOption) error {
+				_, err := c.Parse(context.Background(), nil, opts...)
+				return err
+			},
+			want: http.Header{"X-Tika-Ocrlanguage": {"eng"}},
+		},
+		{
+			name: "WithHeader overrides default",
+			call: func(opts ...RequestOption) error {
+				_, err := c.Parse(context.Background(), nil, opts...)
+				return err
+			},
+			opts: []RequestOption{WithHeader("X-Tika-OCRLanguage", "fra"), WithHeader("X-Tika-OCRLanguage", "deu")},
+			want: http.Header{"X-Tika-Ocrlanguage": {"fra", "deu"}},
+		},
+		{
+			name: "WithHeaders overrides method header",
+			call: func(opts ...RequestOption) error {
+				_, err := c.MIMETypes(context.Background(), opts...)
+				return err
+			},
+			opts: []RequestOption{WithHeaders(http.Header{"Accept": {"text/plain"}, "X-Tika-Skip-Embedded": {"true"}})},
+			want: http.Header{"Accept": {"text/plain"}, "X-Tika-Skip-Embedded": {"true"}, "X-Tika-Ocrlanguage": {"eng"}},
+		},
+	}
+	for _, test := range tests {
+		if err := test.call(test.opts...); err != nil {
+			t.Errorf("%s: got error: %v", test.name, err)
+			continue
+		}
+		for k, v := range test.want {
+			if !reflect.DeepEqual(got[k], v) {
+				t.Errorf("%s: header %q got %q, want %q", test.name, k, got[k], v)
+			}
+		}
+	}
+}
+
+func TestRequestDefaults(t *testing.T) {
+	var sent http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithRequestDefaults(WithSkipEmbedded(true), WithMaxEmbeddedResources(10)))
+
+	if _, err := c.Parse(context.Background(), nil); err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if got, want := sent.Get("X-Tika-Skip-Embedded"), "true"; got != want {
+		t.Errorf("Parse sent X-Tika-Skip-Embedded %q, want %q", got, want)
+	}
+	if got, want := sent.Get("maxEmbeddedResources"), "10"; got != want {
+		t.Errorf("Parse sent maxEmbeddedResources %q, want %q", got, want)
+	}
+
+	if _, err := c.Parse(context.Background(), nil, WithSkipEmbedded(false)); err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if got, want := sent["X-Tika-Skip-Embedded"], []string{"false"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse(WithSkipEmbedded(false)) sent X-Tika-Skip-Embedded %q, want %q", got, want)
+	}
+}
+
+func TestWithFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"report.pdf", "attachment; filename=report.pdf"},
+		{"my report.docx", `attachment; filename="my report.docx"`},
+	}
+	for _, test := range tests {
+		cfg := newRequestConfig(nil, []RequestOption{WithFilename(test.name)})
+		if got := cfg.header.Get("Content-Disposition"); got != test.want {
+			t.Errorf("WithFilename(%q) set Content-Disposition %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWithFileURL(t *testing.T) {
+	var sentURL string
+	var bodyLen int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		sentURL, bodyLen = r.Header.Get("fileUrl"), len(b)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+
+	abs, err := filepath.Abs("testdata/a b.pdf")
+	if err != nil {
+		t.Fatalf("filepath.Abs got error: %v", err)
+	}
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"file:///data/a.pdf", "file:///data/a.pdf"},
+		{"/data/a b.pdf", "file:///data/a%20b.pdf"},
+		{"testdata/a b.pdf", fileURL(abs)},
+	}
+	for _, test := range tests {
+		if _, err := c.Parse(context.Background(), strings.NewReader("not sent"), WithFileURL(test.file)); err != nil {
+			t.Errorf("Parse(WithFileURL(%q)) got error: %v", test.file, err)
+			continue
+		}
+		if sentURL != test.want {
+			t.Errorf("Parse(WithFileURL(%q)) sent fileUrl %q, want %q", test.file, sentURL, test.want)
+		}
+		if bodyLen != 0 {
+			t.Errorf("Parse(WithFileURL(%q)) sent a %d byte body, want none", test.file, bodyLen)
+		}
+	}
+}
+
+func TestFileURL(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/data/a b.pdf", "file:///data/a%20b.pdf"},
+		{"C:/data/a.pdf", "file:///C:/data/a.pdf"},
+	}
+	for _, test := range tests {
+		if got := fileURL(test.path); got != test.want {
+			t.Errorf("fileURL(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// A RetryPolicy controls how a Client retries failed requests. See WithRetry.
+// Zero fields use the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including
+	// the first attempt. The default is 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. The wait doubles
+	// after every attempt, up to MaxBackoff, and is randomized by up to half
+	// to avoid every client retrying at once. The defaults are 500ms and 30s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxElapsed, if positive, is the total time after which a request is no
+	// longer retried.
+	MaxElapsed time.Duration
+	// MaxBufferSize is the size up to which a document that can't be read
+	// again is buffered in memory, so the request can be retried. Documents
+	// that implement io.Seeker, like an *os.File, are rewound instead. Larger
+	// documents are sent once, without retries. The default is 10 MiB, and a
+	// negative size disables buffering.
+	MaxBufferSize int64
+}
+
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMaxBufferSize  = 10 << 20
+)
+
+// retryableStatus are the response codes of requests worth retrying.
+var retryableStatus = map[int]bool{
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// canRetry reports whether req can safely be sent again.
+func canRetry(req *http.Request) bool {
+	switch req.Method {
+	case "GET", "HEAD", "PUT":
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewindable sets req.GetBody, if it isn't set, so that req can be retried
+// after the first attempt consumed input, its body. If input is an io.Seeker,
+// it is rewound to its current offset. Otherwise, up to the policy's
+// MaxBufferSize bytes are buffered; larger inputs are left as they are.
+func (p *RetryPolicy) rewindable(req *http.Request, input io.Reader) error {
+	if input == nil || req.GetBody != nil {
+		return nil
+	}
+	if s, ok := input.(io.Seeker); ok {
+		offset, err := s.Seek(0, io.SeekCurrent)
+		if err == nil {
+			// Don't let the transport close input, it's needed for retries.
+			req.Body = ioutil.NopCloser(input)
+			req.GetBody = func() (io.ReadCloser, error) {
+				if _, err := s.Seek(offset, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return ioutil.NopCloser(input), nil
+			}
+			return nil
+		}
+		// Not every Seeker can seek, like os.Stdin, so try buffering.
+	}
+	max := p.MaxBufferSize
+	if max == 0 {
+		max = defaultMaxBufferSize
+	}
+	if max < 0 {
+		return nil
+	}
+	buf, err := ioutil.ReadAll(io.LimitReader(input, max+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(buf)) > max {
+		// Too large to buffer, so send it once.
+		body := keepCloser(io.MultiReader(bytes.NewReader(buf), input), input)
+		if rc, ok := body.(io.ReadCloser); ok {
+			req.Body = rc
+		} else {
+			req.Body = ioutil.NopCloser(body)
+		}
+		return nil
+	}
+	if c, ok := input.(io.Closer); ok {
+		c.Close()
+	}
+	req.ContentLength = int64(len(buf))
+	req.Body = ioutil.NopCloser(bytes.NewReader(buf))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf)), nil
+	}
+	return nil
+}
+
+// shouldRetry reports whether a request that failed with err is worth
+// retrying.
+func shouldRetry(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || err == ErrCircuitOpen {
+		return false
+	}
+	if e, ok := err.(*Error); ok {
+		return retryableStatus[e.StatusCode]
+	}
+	var tooLarge *UploadTooLargeError
+	if errors.As(err, &tooLarge) {
+		return false
+	}
+	// Any other error comes from sending the request, like a refused
+	// connection.
+	return true
+}
+
+// backoff returns the wait before the given retry, starting at 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d, max := p.InitialBackoff, p.MaxBackoff
+	if d <= 0 {
+		d = defaultInitialBackoff
+	}
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	// Wait between half and all of d.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// do sends req using send, retrying according to p. onRetry, if not nil, is
+// called before each retry.
+func (p *RetryPolicy) do(ctx context.Context, req *http.Request, send func(*http.Request) (*http.Response, error), onRetry func()) (*http.Response, error) {
+	max := p.MaxAttempts
+	if max <= 0 {
+		max = defaultMaxAttempts
+	}
+	if !canRetry(req) {
+		max = 1
+	}
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		resp, err := send(req)
+		if err == nil || attempt >= max || !shouldRetry(ctx, err) {
+			return resp, err
+		}
+		wait := p.backoff(attempt)
+		if p.MaxElapsed > 0 && time.Since(start)+wait > p.MaxElapsed {
+			return nil, err
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, err
+		case <-t.C:
+		}
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+		if onRetry != nil {
+			onRetry()
+		}
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with status, then echoes the
+// request body. It records the number of requests in *attempts.
+func flakyServer(failures, status int, attempts *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*attempts++
+		if *attempts <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s", body)
+	}))
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	tests := []struct {
+		name         string
+		failures     int
+		status       int
+		call         func(*Client) (string, error)
+		maxBuf       int64
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "recovers",
+			failures:     2,
+			status:       http.StatusServiceUnavailable,
+			call:         func(c *Client) (string, error) { return c.Parse(context.Background(), strings.NewReader("body")) },
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up",
+			failures:     5,
+			status:       http.StatusBadGateway,
+			call:         func(c *Client) (string, error) { return c.Version(context.Background()) },
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+		{
+			name:         "not retryable status",
+			failures:     1,
+			status:       http.StatusInternalServerError,
+			call:         func(c *Client) (string, error) { return c.Version(context.Background()) },
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "not idempotent",
+			failures:     1,
+			status:       http.StatusServiceUnavailable,
+			call:         func(c *Client) (string, error) { return c.Translate(context.Background(), nil, "t", "en", "fr") },
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:     "body is buffered",
+			failures: 1,
+			status:   http.StatusServiceUnavailable,
+			call: func(c *Client) (string, error) {
+				return c.Parse(context.Background(), ioutil.NopCloser(strings.NewReader("body")))
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:     "body is rewound",
+			failures: 2,
+			status:   http.StatusServiceUnavailable,
+			call: func(c *Client) (string, error) {
+				r := strings.NewReader("skipbody")
+				r.Seek(4, io.SeekStart)
+				// Hide the type from http.NewRequest, so GetBody isn't set.
+				return c.Parse(context.Background(), struct{ io.ReadSeeker }{r})
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:     "body too large to buffer",
+			failures: 1,
+			status:   http.StatusServiceUnavailable,
+			maxBuf:   3,
+			call: func(c *Client) (string, error) {
+				return c.Parse(context.Background(), ioutil.NopCloser(strings.NewReader("body")))
+			},
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+	}
+	for _, test := range tests {
+		var attempts int
+		ts := flakyServer(test.failures, test.status, &attempts)
+		p := policy
+		p.MaxBufferSize = test.maxBuf
+		c := NewClientWithOptions(ts.URL, WithRetry(p))
+		got, err := test.call(c)
+		ts.Close()
+		if attempts != test.wantAttempts {
+			t.Errorf("%s: got %d attempts, want %d", test.name, attempts, test.wantAttempts)
+		}
+		if !test.wantErr {
+			if err != nil {
+				t.Errorf("%s: got error: %v", test.name, err)
+			} else if got != "body" {
+				t.Errorf("%s: got %q, want the request body to be resent", test.name, got)
+			}
+			continue
+		}
+		if e, ok := err.(*Error); !ok || e.StatusCode != test.status {
+			t.Errorf("%s: got error %v, want an *Error with status %d", test.name, err, test.status)
+		}
+	}
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	var attempts int
+	ts := flakyServer(5, http.StatusServiceUnavailable, &attempts)
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithRetry(RetryPolicy{
+		MaxAttempts:    10,
+		InitialBackoff: time.Hour,
+		MaxElapsed:     time.Second,
+	}))
+	if _, err := c.Version(context.Background()); !errors.Is(err, ErrServerBusy) {
+		t.Errorf("Version got error %v, want %v", err, ErrServerBusy)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1 since the backoff exceeds MaxElapsed", attempts)
+	}
+}
+
+func TestRetryConnectionError(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+	start := time.Now()
+	c := NewClientWithOptions(url, WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: 50 * time.Millisecond}))
+	if _, err := c.Version(context.Background()); err == nil {
+		t.Fatalf("Version got no error, want an error")
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("Version returned after %v, want it to back off and retry", elapsed)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{2, 100 * time.Millisecond, 200 * time.Millisecond},
+		{3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{10, 500 * time.Millisecond, time.Second},
+	}
+	for _, test := range tests {
+		for i := 0; i < 10; i++ {
+			if got := p.backoff(test.retry); got < test.min || got > test.max {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", test.retry, got, test.min, test.max)
+			}
+		}
+	}
+}
@@ -17,16 +17,23 @@ limitations under the License.
 package tika
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha512"
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"log/slog"
+	"net"
 	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"golang.org/x/net/context/ctxhttp"
 )
 
 // Server represents a Tika server. Create a new Server with NewServer,
@@ -35,10 +42,42 @@ import (
 // There is no need to create a Server for an already running Tika Server
 // since you can pass its URL directly to a Client.
 type Server struct {
-	jar  string
-	url  string // url is derived from port.
-	port string
-	cmd  *exec.Cmd
+	jar            string
+	url            string // url is derived from hostname and port.
+	port           string
+	host           string // host is the address the server binds to.
+	hostname       string // hostname is the host clients use to reach the server.
+	cmd            *exec.Cmd
+	java           string
+	version        Version
+	jvmArgs        []string
+	classpath      []string
+	heap           string
+	config         string
+	digest         string
+	cors           string
+	startupTimeout time.Duration
+	pollInterval   time.Duration
+	fileURL        bool
+	unsecure       bool
+
+	spawnChild      bool
+	maxChildStartup time.Duration
+	pingPulse       time.Duration
+	pingTimeout     time.Duration
+	taskTimeout     time.Duration
+
+	output    io.Writer
+	tlsConfig *tls.Config
+	tracer    Tracer
+	logger    *slog.Logger
+
+	// exited is closed when the process started by Start exits. waitErr is
+	// the error returned by cmd.Wait and must only be read after exited is
+	// closed. stopping is set, atomically, once the process is asked to exit.
+	exited   chan struct{}
+	waitErr  error
+	stopping int32
 }
 
 // URL returns the URL of this Server.
@@ -46,8 +85,305 @@ func (s *Server) URL() string {
 	return s.url
 }
 
-// NewServer creates a new Server. The default port is 9998.
-func NewServer(jar, port string) (*Server, error) {
+// Done returns a channel that is closed when the process started by the last
+// call to Start exits, whether it crashed or was stopped. See ExitError for
+// why it exited. Before Start, Done returns nil, and receiving from it blocks
+// forever.
+func (s *Server) Done() <-chan struct{} {
+	return s.exited
+}
+
+// ExitError returns the error the process exited with, as returned by
+// exec.Cmd.Wait, such as an *exec.ExitError. It returns nil if the process
+// exited successfully or hasn't exited yet.
+func (s *Server) ExitError() error {
+	select {
+	case <-s.exited:
+		return s.waitErr
+	default:
+		return nil
+	}
+}
+
+// Process returns the Java process started by the last call to Start, or nil
+// if s hasn't been started.
+func (s *Server) Process() *os.Process {
+	if s.cmd == nil {
+		return nil
+	}
+	return s.cmd.Process
+}
+
+// PID returns the process ID of the Java process started by the last call to
+// Start, or 0 if s hasn't been started.
+func (s *Server) PID() int {
+	if p := s.Process(); p != nil {
+		return p.Pid
+	}
+	return 0
+}
+
+// A ServerOption configures a Server. See NewServer.
+type ServerOption func(*Server)
+
+// WithJavaPath sets the Java binary used to start the server. By default, the
+// binary is located with FindJava.
+func WithJavaPath(path string) ServerOption {
+	return func(s *Server) {
+		s.java = path
+	}
+}
+
+// WithJVMArgs appends args to the arguments passed to the JVM. They are passed
+// before -jar, so they cannot be used to pass flags to Tika Server itself.
+func WithJVMArgs(args ...string) ServerOption {
+	return func(s *Server) {
+		s.jvmArgs = append(s.jvmArgs, args...)
+	}
+}
+
+// WithClasspathJars adds jars, such as ones with custom parsers and
+// detectors, to the classpath of the server. Java is then started with -cp
+// and the main class of Tika Server rather than with -jar. The main class
+// moved in Tika 2.x, so use WithTikaVersion if the jar isn't Tika 1.x.
+func WithClasspathJars(jars ...string) ServerOption {
+	return func(s *Server) {
+		s.classpath = append(s.classpath, jars...)
+	}
+}
+
+// WithHeapSize sets the maximum heap size of the JVM, for example "2g". It is
+// passed to Java as -Xmx.
+func WithHeapSize(size string) ServerOption {
+	return func(s *Server) {
+		s.heap = size
+	}
+}
+
+// WithTempDir sets the directory the JVM, and so Tika, writes temporary files
+// to (-Djava.io.tmpdir). Tika writes a temporary copy of many documents while
+// parsing them, so use a directory with enough space for the largest. The
+// directory must exist.
+func WithTempDir(dir string) ServerOption {
+	return WithJVMArgs("-Djava.io.tmpdir=" + dir)
+}
+
+// WithFileEncoding sets the default character encoding of the JVM
+// (-Dfile.encoding), for example "UTF-8".
+func WithFileEncoding(encoding string) ServerOption {
+	return WithJVMArgs("-Dfile.encoding=" + encoding)
+}
+
+// WithLocale sets the default locale of the JVM (-Duser.language and
+// -Duser.country), for example WithLocale("en", "US"), which affects how some
+// parsers format numbers and dates. country may be empty.
+func WithLocale(language, country string) ServerOption {
+	args := []string{"-Duser.language=" + language}
+	if country != "" {
+		args = append(args, "-Duser.country="+country)
+	}
+	return WithJVMArgs(args...)
+}
+
+// WithHeadless runs the JVM in headless mode (-Djava.awt.headless=true), so
+// rendering images, for example for OCR, doesn't need a display.
+func WithHeadless() ServerOption {
+	return WithJVMArgs("-Djava.awt.headless=true")
+}
+
+// WithConfigFile sets the Tika config file (for example, tika-config.xml) the
+// server is started with. The config file can be used to enable or disable
+// specific parsers and detectors.
+func WithConfigFile(path string) ServerOption {
+	return func(s *Server) {
+		s.config = path
+	}
+}
+
+// WithDigest makes the server compute digests of every document it parses
+// (-digest), for example WithDigest("md5", "sha256"). Tika adds them to the
+// metadata of the document; see Metadata.Digest.
+func WithDigest(algorithms ...string) ServerOption {
+	return func(s *Server) {
+		s.digest = strings.Join(algorithms, ",")
+	}
+}
+
+// WithCORS allows browsers to call the server from origin (-cors), for
+// example "https://example.com", or from any origin if origin is "all".
+func WithCORS(origin string) ServerOption {
+	return func(s *Server) {
+		s.cors = origin
+	}
+}
+
+// UnsecureOptIn acknowledges that an option enables Tika Server features that
+// let any client that can reach the server read files on the server's host or
+// make it fetch URLs. Pass UnsecureOptIn{} to WithUnsecureFeatures or
+// WithEnableFileURL only for servers untrusted clients can't reach.
+type UnsecureOptIn struct{}
+
+// WithUnsecureFeatures enables Tika Server's unsecure features
+// (-enableUnsecureFeatures). On its own it enables none of them; see
+// WithEnableFileURL.
+func WithUnsecureFeatures(UnsecureOptIn) ServerOption {
+	return func(s *Server) {
+		s.unsecure = true
+	}
+}
+
+// WithEnableFileURL lets clients pass documents by reference with WithFileURL
+// (-enableUnsecureFeatures -enableFileUrl), so the server reads them from its
+// own file system instead of the request body.
+func WithEnableFileURL(UnsecureOptIn) ServerOption {
+	return func(s *Server) {
+		s.unsecure = true
+		s.fileURL = true
+	}
+}
+
+// ErrStartupTimeout is returned by Start if the server doesn't respond within
+// the WithStartupTimeout.
+var ErrStartupTimeout = errors.New("tika: server did not start in time")
+
+// WithStartupTimeout sets how long Start waits for the server to respond,
+// independently of the deadline of its context. After d, Start kills the
+// process and returns an error wrapping ErrStartupTimeout. By default, Start
+// waits until its context is done.
+func WithStartupTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.startupTimeout = d
+	}
+}
+
+// WithPollInterval sets how often Start checks whether the server responds.
+// The default is 500ms.
+func WithPollInterval(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.pollInterval = d
+	}
+}
+
+// WithSpawnChild starts Tika Server in child mode (-spawnChild). The parent
+// process forks a child JVM that does the parsing and restarts it if it hangs
+// or runs out of memory.
+func WithSpawnChild() ServerOption {
+	return func(s *Server) {
+		s.spawnChild = true
+	}
+}
+
+// WithMaxChildStartup sets how long the parent waits for the child process to
+// start (-maxChildStartupMillis). Only used in child mode.
+func WithMaxChildStartup(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.maxChildStartup = d
+	}
+}
+
+// WithPingPulse sets how often the parent pings the child process
+// (-pingPulseMillis). Only used in child mode.
+func WithPingPulse(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.pingPulse = d
+	}
+}
+
+// WithPingTimeout sets how long the parent waits for the child process to
+// respond to a ping before restarting it (-pingTimeoutMillis). Only used in
+// child mode.
+func WithPingTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.pingTimeout = d
+	}
+}
+
+// WithTaskTimeout sets how long a single parse may take before the child
+// process is restarted (-taskTimeoutMillis). Only used in child mode.
+func WithTaskTimeout(d time.Duration) ServerOption {
+	return func(s *Server) {
+		s.taskTimeout = d
+	}
+}
+
+// WithOutput streams the stdout and stderr of the server process to w for as
+// long as the process runs. By default, the output is discarded.
+func WithOutput(w io.Writer) ServerOption {
+	return func(s *Server) {
+		s.output = w
+	}
+}
+
+// WithLogger logs every line the server process writes to stdout or stderr
+// to l.
+func WithLogger(l *log.Logger) ServerOption {
+	return WithOutput(&logWriter{l: l})
+}
+
+// logWriter is an io.Writer that logs each complete line written to it.
+type logWriter struct {
+	l   *log.Logger
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.l.Print(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// WithBindAddress sets the host or IP address the server listens on (-h), for
+// example "0.0.0.0" to accept connections on all interfaces. The default is
+// Tika Server's own default, localhost.
+func WithBindAddress(host string) ServerOption {
+	return func(s *Server) {
+		s.host = host
+	}
+}
+
+// WithHostname sets the hostname used in the Server URL, which clients use to
+// reach the server. The default is "localhost".
+func WithHostname(hostname string) ServerOption {
+	return func(s *Server) {
+		s.hostname = hostname
+	}
+}
+
+// WithTLS makes the Server URL use https and uses cfg to connect to the server
+// while waiting for it to start. TLS itself must be set up separately, either
+// in the Tika config file (see WithConfigFile) or by a TLS terminating proxy in
+// front of the server. cfg may be nil to use the system roots.
+func WithTLS(cfg *tls.Config) ServerOption {
+	return func(s *Server) {
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		s.tlsConfig = cfg
+	}
+}
+
+// WithTikaVersion tells the Server which version of Tika Server the jar is, so
+// Start can check the installed Java is new enough. The default assumes a
+// 1.x or 2.x jar, which requires Java 8.
+func WithTikaVersion(v Version) ServerOption {
+	return func(s *Server) {
+		s.version = v
+	}
+}
+
+// NewServer creates a new Server. The default port is 9998. opts are applied
+// in order.
+func NewServer(jar, port string, opts ...ServerOption) (*Server, error) {
 	if jar == "" {
 		return nil, fmt.Errorf("no jar file specified")
 	}
@@ -55,10 +391,18 @@ func NewServer(jar, port string) (*Server, error) {
 		port = "9998"
 	}
 	s := &Server{
-		jar:  jar,
-		port: port,
+		jar:      jar,
+		port:     port,
+		hostname: "localhost",
 	}
-	urlString := "http://localhost:" + s.port
+	for _, opt := range opts {
+		opt(s)
+	}
+	scheme := "http"
+	if s.tlsConfig != nil {
+		scheme = "https"
+	}
+	urlString := scheme + "://" + net.JoinHostPort(s.hostname, s.port)
 	u, err := url.Parse(urlString)
 	if err != nil {
 		return nil, fmt.Errorf("invalid port %q: %v", s.port, err)
@@ -69,34 +413,209 @@ func NewServer(jar, port string) (*Server, error) {
 
 var command = exec.Command
 
+// serverMainClass returns the main class of version v of Tika Server. An
+// unknown version is assumed to be 1.x.
+func serverMainClass(v Version) string {
+	if v == "" || strings.HasPrefix(string(v), "1.") {
+		return "org.apache.tika.server.TikaServerCli"
+	}
+	return "org.apache.tika.server.core.TikaServerCli"
+}
+
+// args returns the arguments passed to Java to start s. They are passed
+// unquoted, even if they contain spaces; on Windows, os/exec quotes them.
+func (s *Server) args() []string {
+	var args []string
+	args = append(args, s.jvmArgs...)
+	if s.heap != "" {
+		args = append(args, "-Xmx"+s.heap)
+	}
+	if len(s.classpath) > 0 {
+		cp := append(append([]string(nil), s.classpath...), s.jar)
+		args = append(args, "-cp", strings.Join(cp, string(os.PathListSeparator)), serverMainClass(s.version))
+	} else {
+		args = append(args, "-jar", s.jar)
+	}
+	args = append(args, "-p", s.port)
+	if s.host != "" {
+		args = append(args, "-h", s.host)
+	}
+	if s.config != "" {
+		args = append(args, "--config", s.config)
+	}
+	if s.digest != "" {
+		args = append(args, "-digest", s.digest)
+	}
+	if s.cors != "" {
+		args = append(args, "-cors", s.cors)
+	}
+	if s.unsecure {
+		args = append(args, "-enableUnsecureFeatures")
+	}
+	if s.fileURL {
+		args = append(args, "-enableFileUrl")
+	}
+	if s.spawnChild {
+		args = append(args, "-spawnChild")
+	}
+	millisFlags := []struct {
+		flag string
+		d    time.Duration
+	}{
+		{"-maxChildStartupMillis", s.maxChildStartup},
+		{"-pingPulseMillis", s.pingPulse},
+		{"-pingTimeoutMillis", s.pingTimeout},
+		{"-taskTimeoutMillis", s.taskTimeout},
+	}
+	for _, f := range millisFlags {
+		if f.d > 0 {
+			args = append(args, f.flag, strconv.FormatInt(int64(f.d/time.Millisecond), 10))
+		}
+	}
+	return args
+}
+
 // Start starts the given server. Start will start a new Java process. The
 // caller must call Stop() to shut down the process when finished with the
 // Server. Start will wait for the server to be available or until ctx is
-// cancelled.
-func (s *Server) Start(ctx context.Context) error {
-	cmd := command("java", "-jar", s.jar, "-p", s.port)
+// cancelled. Start returns an error if Java cannot be found or is too old. If
+// the server fails to start, Start kills the process before returning.
+func (s *Server) Start(ctx context.Context) (err error) {
+	ctx, end := s.startSpan(ctx, "tika.server.start")
+	defer func() { end(err) }()
+	min := 8
+	if s.version != "" {
+		min = MinJavaVersion(s.version)
+	}
+	java, err := checkJava(ctx, s.java, min)
+	if err != nil {
+		return err
+	}
+	cmd := command(java, s.args()...)
+	// Capture the end of the output from the start, so that a startup
+	// failure can report why the server failed.
+	tail := &ringBuffer{max: startupOutputSize}
+	var out io.Writer = tail
+	if s.output != nil {
+		out = io.MultiWriter(s.output, tail)
+	}
+	cmd.Stdout = out
+	cmd.Stderr = out
 
+	logAttrs(ctx, s.logger, slog.LevelInfo, "starting tika server", slog.String("url", s.url), slog.String("jar", s.jar))
+	started := time.Now()
+	defer func() {
+		if err != nil {
+			logAttrs(ctx, s.logger, slog.LevelError, "tika server failed to start", slog.String("url", s.url), slog.Any("error", err))
+			return
+		}
+		logAttrs(ctx, s.logger, slog.LevelInfo, "tika server started", slog.String("url", s.url), slog.Duration("duration", time.Since(started)))
+	}()
 	if err := cmd.Start(); err != nil {
 		return err
 	}
 	s.cmd = cmd
+	exited := make(chan struct{})
+	s.exited = exited
+	atomic.StoreInt32(&s.stopping, 0)
+	go func() {
+		s.waitErr = cmd.Wait()
+		if atomic.LoadInt32(&s.stopping) == 0 {
+			logAttrs(context.Background(), s.logger, slog.LevelError, "tika server exited unexpectedly", slog.String("url", s.url), slog.Any("error", s.waitErr))
+		} else {
+			logAttrs(context.Background(), s.logger, slog.LevelInfo, "tika server stopped", slog.String("url", s.url))
+		}
+		close(exited)
+	}()
 
 	if err := s.waitForStart(ctx); err != nil {
-		out, readErr := cmd.CombinedOutput()
-		if readErr != nil {
-			return fmt.Errorf("error reading output: %v", readErr)
+		if killErr := s.kill(); killErr != nil {
+			logAttrs(context.Background(), s.logger, slog.LevelError, "could not stop tika server", slog.String("url", s.url), slog.Any("error", killErr))
+		}
+		out := tail.Bytes()
+		if len(out) == 0 {
+			return fmt.Errorf("error starting server: %w", err)
 		}
-		// Report stderr since sometimes the server says why it failed to start.
-		return fmt.Errorf("error starting server: %v\nserver stderr:\n\n%s", err, out)
+		// Report the output since the server usually says why it failed to
+		// start.
+		return fmt.Errorf("error starting server: %w\nserver output:\n\n%s", err, out)
 	}
 	return nil
 }
 
-// waitForServer waits until the given Server is responding to requests or
-// ctx is Done().
-func (s Server) waitForStart(ctx context.Context) error {
+// startupOutputSize is how much of the end of the server output a startup
+// error includes.
+const startupOutputSize = 16 << 10
+
+// ringBuffer is an io.Writer that keeps the last max bytes written to it.
+type ringBuffer struct {
+	max int
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *ringBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	if len(p) > b.max {
+		p = p[len(p)-b.max:]
+	}
+	if over := len(b.buf) + len(p) - b.max; over > 0 {
+		b.buf = b.buf[:copy(b.buf, b.buf[over:])]
+	}
+	b.buf = append(b.buf, p...)
+	return n, nil
+}
+
+// Bytes returns a copy of the bytes kept.
+func (b *ringBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf...)
+}
+
+// waitForStart waits until the given Server is responding to requests, its
+// process exits, the startup timeout elapses or ctx is Done().
+func (s *Server) waitForStart(ctx context.Context) error {
 	c := NewClient(nil, s.url)
-	t := time.NewTicker(500 * time.Millisecond)
+	if s.tlsConfig != nil {
+		c = NewTLSClient(s.tlsConfig, s.url)
+	}
+	waitCtx := ctx
+	if s.startupTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, s.startupTimeout)
+		defer cancel()
+	}
+	interval := s.pollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	err := waitForServer(waitCtx, c, interval, s.exited)
+	if err == context.DeadlineExceeded && ctx.Err() == nil {
+		return ErrStartupTimeout
+	}
+	if err != errExited {
+		return err
+	}
+	if s.waitErr != nil {
+		return fmt.Errorf("server exited: %v", s.waitErr)
+	}
+	return errExited
+}
+
+// errExited is returned by waitForServer if the server exits.
+var errExited = errors.New("server exited")
+
+// defaultPollInterval is how often a starting server is checked by default.
+const defaultPollInterval = 500 * time.Millisecond
+
+// waitForServer waits until the server c connects to is responding to
+// requests, checking every interval, until exited is closed or ctx is Done().
+// exited may be nil.
+func waitForServer(ctx context.Context, c *Client, interval time.Duration, exited <-chan struct{}) error {
+	t := time.NewTicker(interval)
 	defer t.Stop()
 	for {
 		select {
@@ -104,6 +623,8 @@ func (s Server) waitForStart(ctx context.Context) error {
 			if _, err := c.Version(ctx); err == nil {
 				return nil
 			}
+		case <-exited:
+			return errExited
 		case <-ctx.Done():
 			return ctx.Err()
 		}
@@ -112,93 +633,66 @@ func (s Server) waitForStart(ctx context.Context) error {
 
 // Stop shuts the server down, killing the underlying Java process. Stop
 // must be called when finished with the server to avoid leaking the
-// Java process. If s has not been started, Stop will panic.
-func (s *Server) Stop() error {
-	if err := s.cmd.Process.Kill(); err != nil {
-		return fmt.Errorf("could not kill server: %v", err)
-	}
-	if err := s.cmd.Wait(); err != nil {
-		return fmt.Errorf("could not wait for server to finish: %v", err)
-	}
-	return nil
+// Java process. If the process was never started or has already exited,
+// Stop does nothing.
+func (s *Server) Stop() (err error) {
+	_, end := s.startSpan(context.Background(), "tika.server.stop")
+	defer func() { end(err) }()
+	return s.kill()
 }
 
-func sha512Hash(path string) (string, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return "", err
+// kill kills the process, if it is running, and waits for it to exit.
+func (s *Server) kill() error {
+	if s.cmd == nil {
+		return nil
 	}
-	defer f.Close()
-
-	h := sha512.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", err
+	atomic.StoreInt32(&s.stopping, 1)
+	select {
+	case <-s.exited:
+		return nil
+	default:
+	}
+	if err := killProcess(s.cmd.Process); err != nil {
+		return fmt.Errorf("could not kill server: %v", err)
+	}
+	<-s.exited
+	// An exit status is expected, the process was just killed.
+	var exitErr *exec.ExitError
+	if s.waitErr != nil && !errors.As(s.waitErr, &exitErr) {
+		return fmt.Errorf("could not wait for server to finish: %v", s.waitErr)
 	}
-	return fmt.Sprintf("%x", h.Sum(nil)), nil
+	return nil
 }
 
-// A Version represents a Tika Server version.
-type Version string
-
-// Supported versions of Tika Server.
-const (
-	Version119 Version = "1.19"
-	Version120 Version = "1.20"
-	Version121 Version = "1.21"
-)
-
-// Versions is a list of supported versions of Apache Tika.
-var Versions = []Version{Version119, Version120, Version121}
-
-var sha512s = map[Version]string{
-	Version119: "a9e2b6186cdb9872466d3eda791d0e1cd059da923035940d4b51bb1adc4a356670fde46995725844a2dd500a09f3a5631d0ca5fbc2d61a59e8e0bd95c9dfa6c2",
-	Version120: "a7ef35317aba76be8606f9250893efece8b93384e835a18399da18a095b19a15af591e3997828d4ebd3023f21d5efad62a91918610c44e692cfd9bed01d68382",
-	Version121: "e705c836b2110530c8d363d05da27f65c4f6c9051b660cefdae0e5113c365dbabed2aa1e4171c8e52dbe4cbaa085e3d8a01a5a731e344942c519b85836da646c",
-}
-
-// DownloadServer downloads and validates the given server version,
-// saving it at path. DownloadServer returns an error if it could
-// not be downloaded/validated.
-// It is the caller's responsibility to remove the file when no longer needed.
-// If the file already exists and has the correct sha512, DownloadServer will
-// do nothing.
-func DownloadServer(ctx context.Context, v Version, path string) error {
-	hash := sha512s[v]
-	if hash == "" {
-		return fmt.Errorf("unsupported Tika version: %s", v)
-	}
-	if got, err := sha512Hash(path); err == nil {
-		if got == hash {
-			return nil
-		}
+// Shutdown gracefully shuts the server down. Shutdown asks the Java process to
+// terminate (SIGTERM), giving it a chance to finish in-flight requests, and
+// waits for it to exit. If ctx is done before the process exits, Shutdown
+// kills the process and returns ctx.Err(). Like Stop, Shutdown does nothing if
+// the process was never started or has already exited.
+func (s *Server) Shutdown(ctx context.Context) (err error) {
+	_, end := s.startSpan(ctx, "tika.server.shutdown")
+	defer func() { end(err) }()
+	if s.cmd == nil {
+		return nil
 	}
-	out, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("error creating file: %v", err)
+	atomic.StoreInt32(&s.stopping, 1)
+	select {
+	case <-s.exited:
+		return nil
+	default:
 	}
-	defer out.Close()
-
-	url := fmt.Sprintf("http://search.maven.org/remotecontent?filepath=org/apache/tika/tika-server/%s/tika-server-%s.jar", v, v)
-	resp, err := ctxhttp.Get(ctx, nil, url)
-	if err != nil {
-		return fmt.Errorf("unable to download %q: %v", url, err)
+	if err := terminateProcess(s.cmd.Process); err != nil {
+		// Not every platform supports SIGTERM, so fall back to killing. On
+		// Windows, which doesn't, kill also kills the child processes.
+		return s.kill()
 	}
-	defer resp.Body.Close()
-
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return fmt.Errorf("error saving download: %v", err)
+	select {
+	case <-s.exited:
+		return nil
+	case <-ctx.Done():
 	}
-
-	h, err := sha512Hash(path)
-
-	if err != nil {
+	if err := s.kill(); err != nil {
 		return err
 	}
-	if h != hash {
-		if err := os.Remove(path); err != nil {
-			return fmt.Errorf("invalid sha512: %s: error removing %s: %v", h, path, err)
-		}
-		return fmt.Errorf("invalid sha512: %s", h)
-	}
-	return nil
+	return ctx.Err()
 }
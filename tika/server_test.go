@@ -17,14 +17,23 @@ limitations under the License.
 package tika
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -33,10 +42,14 @@ func init() {
 	// Overwrite the cmder to inject a dummy command. We simulate starting a server
 	// by running the TestHelperProcess.
 	command = func(string, ...string) *exec.Cmd {
-		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "sleep", "2")
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "echo", "helper started", "sleep", "2")
 		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
 		return c
 	}
+	// Don't require Java to be installed.
+	checkJava = func(_ context.Context, java string, _ int) (string, error) {
+		return java, nil
+	}
 }
 
 func TestNewServerError(t *testing.T) {
@@ -55,6 +68,128 @@ func TestNewServerError(t *testing.T) {
 	}
 }
 
+func TestServerArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []ServerOption
+		wantJava string
+		want     []string
+	}{
+		{
+			name: "default",
+			want: []string{"-jar", "tika.jar", "-p", "9998"},
+		},
+		{
+			name:     "java path",
+			opts:     []ServerOption{WithJavaPath("/opt/java/bin/java")},
+			wantJava: "/opt/java/bin/java",
+			want:     []string{"-jar", "tika.jar", "-p", "9998"},
+		},
+		{
+			name: "jvm args and heap",
+			opts: []ServerOption{WithJVMArgs("-Dfoo=bar"), WithHeapSize("2g"), WithJVMArgs("-server")},
+			want: []string{"-Dfoo=bar", "-server", "-Xmx2g", "-jar", "tika.jar", "-p", "9998"},
+		},
+		{
+			name: "jvm settings",
+			opts: []ServerOption{WithTempDir("/scratch"), WithFileEncoding("UTF-8"), WithLocale("en", "US"), WithLocale("fr", ""), WithHeadless()},
+			want: []string{
+				"-Djava.io.tmpdir=/scratch", "-Dfile.encoding=UTF-8",
+				"-Duser.language=en", "-Duser.country=US", "-Duser.language=fr",
+				"-Djava.awt.headless=true",
+				"-jar", "tika.jar", "-p", "9998",
+			},
+		},
+		{
+			name: "bind address",
+			opts: []ServerOption{WithBindAddress("0.0.0.0")},
+			want: []string{"-jar", "tika.jar", "-p", "9998", "-h", "0.0.0.0"},
+		},
+		{
+			name: "config file",
+			opts: []ServerOption{WithConfigFile("tika-config.xml")},
+			want: []string{"-jar", "tika.jar", "-p", "9998", "--config", "tika-config.xml"},
+		},
+		{
+			name: "classpath jars",
+			opts: []ServerOption{WithClasspathJars("a.jar", "b.jar"), WithHeapSize("1g")},
+			want: []string{
+				"-Xmx1g", "-cp", strings.Join([]string{"a.jar", "b.jar", "tika.jar"}, string(os.PathListSeparator)),
+				"org.apache.tika.server.TikaServerCli", "-p", "9998",
+			},
+		},
+		{
+			name: "classpath jars 2.x",
+			opts: []ServerOption{WithClasspathJars("a.jar"), WithTikaVersion(Version292)},
+			want: []string{
+				"-cp", "a.jar" + string(os.PathListSeparator) + "tika.jar",
+				"org.apache.tika.server.core.TikaServerCli", "-p", "9998",
+			},
+		},
+		{
+			name: "digest",
+			opts: []ServerOption{WithDigest("md5", "sha256")},
+			want: []string{"-jar", "tika.jar", "-p", "9998", "-digest", "md5,sha256"},
+		},
+		{
+			name: "cors",
+			opts: []ServerOption{WithCORS("all")},
+			want: []string{"-jar", "tika.jar", "-p", "9998", "-cors", "all"},
+		},
+		{
+			name: "unsecure features",
+			opts: []ServerOption{WithUnsecureFeatures(UnsecureOptIn{})},
+			want: []string{"-jar", "tika.jar", "-p", "9998", "-enableUnsecureFeatures"},
+		},
+		{
+			name: "file url",
+			opts: []ServerOption{WithEnableFileURL(UnsecureOptIn{})},
+			want: []string{"-jar", "tika.jar", "-p", "9998", "-enableUnsecureFeatures", "-enableFileUrl"},
+		},
+		{
+			name: "child mode",
+			opts: []ServerOption{
+				WithSpawnChild(),
+				WithMaxChildStartup(time.Minute),
+				WithPingPulse(500 * time.Millisecond),
+				WithPingTimeout(2 * time.Second),
+				WithTaskTimeout(5 * time.Minute),
+			},
+			want: []string{
+				"-jar", "tika.jar", "-p", "9998", "-spawnChild",
+				"-maxChildStartupMillis", "60000",
+				"-pingPulseMillis", "500",
+				"-pingTimeoutMillis", "2000",
+				"-taskTimeoutMillis", "300000",
+			},
+		},
+	}
+	for _, test := range tests {
+		s, err := NewServer("tika.jar", "", test.opts...)
+		if err != nil {
+			t.Fatalf("NewServer(%s) got error: %v", test.name, err)
+		}
+		if s.java != test.wantJava {
+			t.Errorf("NewServer(%s) java = %q, want %q", test.name, s.java, test.wantJava)
+		}
+		if got := s.args(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("NewServer(%s) args = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestServerArgsSpaces(t *testing.T) {
+	jar := filepath.Join("Program Files", "Tika", "tika server.jar")
+	s, err := NewServer(jar, "", WithConfigFile("my config.xml"))
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	want := []string{"-jar", jar, "-p", "9998", "--config", "my config.xml"}
+	if got := s.args(); !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
 func TestStart(t *testing.T) {
 	path, err := os.Executable() // Use the text executable path as a dummy jar.
 	if err != nil {
@@ -80,6 +215,180 @@ func TestStart(t *testing.T) {
 	s.Stop()
 }
 
+func TestServerProcess(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	defer func(old func(string, ...string) *exec.Cmd) { command = old }(command)
+	command = func(string, ...string) *exec.Cmd {
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "sleep", "1", "exit", "3")
+		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		return c
+	}
+	ts := bouncyServer(0)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	s, err := NewServer(path, tsURL.Port())
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	if s.Done() != nil || s.Process() != nil || s.PID() != 0 || s.ExitError() != nil {
+		t.Errorf("before Start got Done %v, Process %v, PID %d and ExitError %v, want zero values", s.Done(), s.Process(), s.PID(), s.ExitError())
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	if s.PID() <= 0 || s.Process() == nil || s.Process().Pid != s.PID() {
+		t.Errorf("after Start got Process %v and PID %d, want the process", s.Process(), s.PID())
+	}
+	if err := s.ExitError(); err != nil {
+		t.Errorf("ExitError before exit got %v, want nil", err)
+	}
+	select {
+	case <-s.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("Done wasn't closed after the process exited")
+	}
+	var exitErr *exec.ExitError
+	if err := s.ExitError(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("ExitError got %v, want exit status 3", err)
+	}
+}
+
+func TestStartOutput(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	ts := bouncyServer(2)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	var output, logged bytes.Buffer
+	tests := []struct {
+		name string
+		opt  ServerOption
+		buf  *bytes.Buffer
+		want string
+	}{
+		{"output", WithOutput(&output), &output, "helper started\n"},
+		{"logger", WithLogger(log.New(&logged, "tika: ", 0)), &logged, "tika: helper started\n"},
+	}
+	for _, test := range tests {
+		s, err := NewServer(path, tsURL.Port(), test.opt)
+		if err != nil {
+			t.Fatalf("NewServer(%s) got error: %v", test.name, err)
+		}
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatalf("Start(%s) got error: %v", test.name, err)
+		}
+		s.Stop()
+		if got := test.buf.String(); got != test.want {
+			t.Errorf("Start(%s) output = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	defer func(old func(string, ...string) *exec.Cmd) { command = old }(command)
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+	}{
+		{"terminates", []string{"sleep", "5"}, nil},
+		{"ignores sigterm", []string{"ignore", "sigterm", "sleep", "5"}, context.DeadlineExceeded},
+	}
+	for _, test := range tests {
+		args := append([]string{"-test.run=TestHelperProcess", "--"}, test.args...)
+		command = func(string, ...string) *exec.Cmd {
+			c := exec.Command(os.Args[0], args...)
+			c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+			return c
+		}
+		ts := bouncyServer(0)
+		defer ts.Close()
+		tsURL, err := url.Parse(ts.URL)
+		if err != nil {
+			t.Fatalf("error creating test server: %v", err)
+		}
+		s, err := NewServer(path, tsURL.Port())
+		if err != nil {
+			t.Fatalf("NewServer(%s) got error: %v", test.name, err)
+		}
+		if err := s.Start(context.Background()); err != nil {
+			t.Fatalf("Start(%s) got error: %v", test.name, err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != test.wantErr {
+			t.Errorf("Shutdown(%s) got %v, want %v", test.name, err, test.wantErr)
+		}
+		select {
+		case <-s.exited:
+		default:
+			t.Errorf("Shutdown(%s) returned before the process exited", test.name)
+		}
+		// Shutting down again, after the process exited, does nothing.
+		if err := s.Shutdown(ctx); err != nil {
+			t.Errorf("second Shutdown(%s) got %v, want nil", test.name, err)
+		}
+	}
+	// A Server that was never started has nothing to shut down.
+	s, err := NewServer(path, "")
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown of an unstarted server got %v, want nil", err)
+	}
+}
+
+func TestStop(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	defer func(old func(string, ...string) *exec.Cmd) { command = old }(command)
+	command = func(string, ...string) *exec.Cmd {
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "sleep", "5")
+		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		return c
+	}
+	ts := bouncyServer(0)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	s, err := NewServer(path, tsURL.Port())
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop before Start got error: %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	if err := s.Stop(); err != nil {
+		t.Errorf("Stop got error: %v", err)
+	}
+	if err := s.Stop(); err != nil {
+		t.Errorf("second Stop got error: %v", err)
+	}
+}
+
 func bouncyServer(bounce int) *httptest.Server {
 	bounced := 0
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -110,9 +419,85 @@ func TestStartError(t *testing.T) {
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
-	if err := s.Start(ctx); err == nil {
+	err = s.Start(ctx)
+	if err == nil {
+		t.Fatalf("s.Start got no error, want error")
+	}
+	if !strings.Contains(err.Error(), "helper started") {
+		t.Errorf("s.Start got error %q, want it to include the server output", err)
+	}
+	s.Stop()
+}
+
+func TestStartExited(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	defer func(old func(string, ...string) *exec.Cmd) { command = old }(command)
+	command = func(string, ...string) *exec.Cmd {
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "print", "Address already in use", "echo", "exiting")
+		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		return c
+	}
+	ts := bouncyServer(100)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	s, err := NewServer(path, tsURL.Port(), WithOutput(ioutil.Discard))
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = s.Start(ctx)
+	if err == nil {
 		t.Fatalf("s.Start got no error, want error")
 	}
+	if ctx.Err() != nil {
+		t.Errorf("s.Start waited for the context after the server exited")
+	}
+	for _, want := range []string{"server exited", "Address already in use\nexiting\n"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("s.Start got error %q, want it to include %q", err, want)
+		}
+	}
+}
+
+func TestRingBuffer(t *testing.T) {
+	b := &ringBuffer{max: 5}
+	for _, w := range []string{"ab", "cd", "efg", "", "hijklmnop", "q"} {
+		if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+			t.Fatalf("Write(%q) got (%d, %v), want (%d, nil)", w, n, err, len(w))
+		}
+	}
+	if got, want := string(b.Bytes()), "mnopq"; got != want {
+		t.Errorf("Bytes got %q, want %q", got, want)
+	}
+}
+
+func TestNewServerURL(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ServerOption
+		want string
+	}{
+		{"default", nil, "http://localhost:9998"},
+		{"bind address", []ServerOption{WithBindAddress("0.0.0.0")}, "http://localhost:9998"},
+		{"hostname", []ServerOption{WithHostname("tika.internal")}, "http://tika.internal:9998"},
+		{"ipv6 hostname", []ServerOption{WithHostname("::1")}, "http://[::1]:9998"},
+	}
+	for _, test := range tests {
+		s, err := NewServer("tika.jar", "", test.opts...)
+		if err != nil {
+			t.Fatalf("NewServer(%s) got error: %v", test.name, err)
+		}
+		if got := s.URL(); got != test.want {
+			t.Errorf("NewServer(%s).URL() = %q, want %q", test.name, got, test.want)
+		}
+	}
 }
 
 func TestURL(t *testing.T) {
@@ -160,6 +545,84 @@ func TestWaitForStart(t *testing.T) {
 	}
 }
 
+func TestWaitForStartTimeout(t *testing.T) {
+	ts := bouncyServer(1000)
+	defer ts.Close()
+	s := &Server{url: ts.URL, startupTimeout: 200 * time.Millisecond, pollInterval: 10 * time.Millisecond}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := s.waitForStart(ctx); err != ErrStartupTimeout {
+		t.Errorf("waitForStart got %v, want %v", err, ErrStartupTimeout)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("waitForStart waited for the context, not the startup timeout")
+	}
+
+	// The deadline of the context is still an error of the context.
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s.startupTimeout = time.Minute
+	if err := s.waitForStart(ctx); err != context.DeadlineExceeded {
+		t.Errorf("waitForStart got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestStartStartupTimeout(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	ts := bouncyServer(1000)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	s, err := NewServer(path, tsURL.Port(), WithStartupTimeout(300*time.Millisecond), WithPollInterval(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	start := time.Now()
+	err = s.Start(context.Background())
+	if !errors.Is(err, ErrStartupTimeout) {
+		t.Errorf("Start got %v, want %v", err, ErrStartupTimeout)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Start took %v, want about 300ms", d)
+	}
+	// Start doesn't leave the process running.
+	select {
+	case <-s.exited:
+	default:
+		s.Stop()
+		t.Errorf("Start returned with the server process still running")
+	}
+}
+
+func TestWaitForStartTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "1.14")
+	}))
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	cfg := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	s, err := NewServer("tika.jar", tsURL.Port(), WithHostname(tsURL.Hostname()), WithTLS(cfg))
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	if got, want := s.URL(), ts.URL; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.waitForStart(ctx); err != nil {
+		t.Errorf("waitForStart got %v, want no error", err)
+	}
+}
+
 // TestHelperProcess isn't a real test. It's used as a helper process
 // for TestParameterRun.
 // Adapted from os/exec/exec_test.go.
@@ -177,50 +640,29 @@ func TestHelperProcess(*testing.T) {
 		}
 		args = args[1:]
 	}
-	if args[0] == "sleep" {
-		l, err := strconv.Atoi(args[1])
-		if err != nil {
-			os.Exit(1)
-		}
-		time.Sleep(time.Duration(l) * time.Second)
-	}
-}
-
-func TestValidateFileHash(t *testing.T) {
-	path, err := os.Executable()
-	if err != nil {
-		t.Skip("cannot find current test executable")
-	}
-
-	tests := []struct {
-		path    string
-		wantErr bool
-	}{
-		{"path_to_non_existent_file", true},
-		{path, false},
-	}
-	for _, test := range tests {
-		_, err := sha512Hash(test.path)
-		if test.wantErr && err == nil {
-			t.Errorf("getHash(%s) wanted an error", test.path)
-			continue
-		}
-		if !test.wantErr && err != nil {
-			t.Errorf("getHash(%s) got an error: %v", test.path, err)
-		}
-	}
-}
-
-func TestDownloadServerError(t *testing.T) {
-	tests := []struct {
-		version version
-		path    string
-	}{
-		{"1.0", ""},
-	}
-	for _, test := range tests {
-		if err := DownloadServer(context.Background(), test.version, test.path); err == nil {
-			t.Errorf("DownloadServer(%q, %q) got no error, want an error", test.version, test.path)
+	for len(args) >= 2 {
+		switch args[0] {
+		case "echo":
+			fmt.Fprintln(os.Stderr, args[1])
+		case "print":
+			fmt.Fprintln(os.Stdout, args[1])
+		case "ignore":
+			if args[1] == "sigterm" {
+				signal.Ignore(syscall.SIGTERM)
+			}
+		case "exit":
+			code, err := strconv.Atoi(args[1])
+			if err != nil {
+				os.Exit(1)
+			}
+			os.Exit(code)
+		case "sleep":
+			l, err := strconv.Atoi(args[1])
+			if err != nil {
+				os.Exit(1)
+			}
+			time.Sleep(time.Duration(l) * time.Second)
 		}
+		args = args[2:]
 	}
 }
@@ -0,0 +1,124 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Metadata fields set by Tika when it stops extracting text early, for
+// example because of WithWriteLimit.
+const (
+	XTIKAContentTruncated  = "X-TIKA:content_truncated"
+	XTIKAWriteLimitReached = "X-TIKA:EXCEPTION:write_limit_reached"
+)
+
+// TextStats are statistics of the text extracted from a document.
+type TextStats struct {
+	Characters int
+	Words      int
+	Lines      int
+	// Language is the language of the document, if Tika reported it.
+	Language string
+	// Truncated reports whether Tika stopped extracting text early.
+	Truncated bool
+}
+
+// Stats returns the statistics of text, the text of a document with metadata
+// m. m may be nil.
+func Stats(text string, m Metadata) TextStats {
+	var w StatsWriter
+	w.WriteString(text)
+	return w.Stats(m)
+}
+
+// A StatsWriter computes TextStats of the text written to it, so that they can
+// be computed while streaming a parse. For example:
+//
+//	var sw tika.StatsWriter
+//	if _, err := c.ParseTo(ctx, io.MultiWriter(w, &sw), input); err != nil {
+//		return err
+//	}
+//	stats := sw.Stats(nil)
+//
+// The zero value is ready to use.
+type StatsWriter struct {
+	chars, words, lines int
+	inWord, inLine      bool
+	// partial is an incomplete UTF-8 sequence at the end of the last write.
+	partial []byte
+}
+
+// Write counts the text in p. It never returns an error.
+func (w *StatsWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(w.partial) > 0 {
+		p = append(w.partial, p...)
+		w.partial = nil
+	}
+	for len(p) > 0 {
+		if !utf8.FullRune(p) {
+			w.partial = append([]byte(nil), p...)
+			break
+		}
+		r, size := utf8.DecodeRune(p)
+		w.count(r)
+		p = p[size:]
+	}
+	return n, nil
+}
+
+// WriteString is like Write, but writes the contents of s.
+func (w *StatsWriter) WriteString(s string) (int, error) {
+	for _, r := range s {
+		w.count(r)
+	}
+	return len(s), nil
+}
+
+func (w *StatsWriter) count(r rune) {
+	w.chars++
+	if r == '\n' {
+		w.lines++
+		w.inLine = false
+	} else {
+		w.inLine = true
+	}
+	if unicode.IsSpace(r) {
+		w.inWord = false
+	} else if !w.inWord {
+		w.words++
+		w.inWord = true
+	}
+}
+
+// Stats returns the statistics of the text written so far, taking the language
+// and truncation from the metadata m of the document. m may be nil.
+func (w *StatsWriter) Stats(m Metadata) TextStats {
+	s := TextStats{
+		Characters: w.chars,
+		Words:      w.words,
+		Lines:      w.lines,
+		Language:   m.first(MetaLanguage, "language", "Content-Language"),
+		Truncated:  m.firstBool(XTIKAContentTruncated, XTIKAWriteLimitReached),
+	}
+	if w.inLine || len(w.partial) > 0 {
+		s.Lines++
+	}
+	return s
+}
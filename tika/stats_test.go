@@ -0,0 +1,62 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	tests := []struct {
+		text string
+		m    Metadata
+		want TextStats
+	}{
+		{"", nil, TextStats{}},
+		{"one", nil, TextStats{Characters: 3, Words: 1, Lines: 1}},
+		{"one two\n", nil, TextStats{Characters: 8, Words: 2, Lines: 1}},
+		{"  héllo\n\nwörld  ", nil, TextStats{Characters: 16, Words: 2, Lines: 3}},
+		{
+			text: "bonjour",
+			m:    Metadata{"language": {"fr"}, XTIKAContentTruncated: {"true"}},
+			want: TextStats{Characters: 7, Words: 1, Lines: 1, Language: "fr", Truncated: true},
+		},
+	}
+	for _, test := range tests {
+		if got := Stats(test.text, test.m); got != test.want {
+			t.Errorf("Stats(%q) got %+v, want %+v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestStatsWriter(t *testing.T) {
+	const text = "héllo wörld\n日本語"
+	want := Stats(text, nil)
+	// Write one byte at a time, splitting multi-byte characters.
+	var w StatsWriter
+	for i := 0; i < len(text); i++ {
+		if n, err := w.Write([]byte{text[i]}); n != 1 || err != nil {
+			t.Fatalf("Write got (%d, %v), want (1, nil)", n, err)
+		}
+	}
+	if got := w.Stats(nil); got != want {
+		t.Errorf("StatsWriter got %+v, want %+v", got, want)
+	}
+	if want.Characters != 15 || want.Words != 3 || want.Lines != 2 {
+		t.Errorf("Stats(%q) got %+v, want 15 characters, 3 words and 2 lines", text, want)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"time"
+)
+
+// ServerStatus is the operational status of a Tika 2.x server. See Status.
+type ServerStatus struct {
+	// ServerID identifies the server process, and changes when the server
+	// restarts its forked child.
+	ServerID string `json:"server_id"`
+	// Status is the state of the server, for example "OPERATING".
+	Status string `json:"status"`
+	// MillisSinceLastParseStarted is the time since the last parse started,
+	// in milliseconds.
+	MillisSinceLastParseStarted int64 `json:"millis_since_last_parse_started"`
+	// FilesProcessed is the number of files the server has processed.
+	FilesProcessed int64 `json:"files_processed"`
+	// NumRestarts is the number of times the forked child has been restarted.
+	NumRestarts int `json:"num_restarts"`
+}
+
+// SinceLastParse returns the time since the last parse started.
+func (s *ServerStatus) SinceLastParse() time.Duration {
+	return time.Duration(s.MillisSinceLastParseStarted) * time.Millisecond
+}
+
+// Status returns the operational status of the server. It requires Tika 2.x
+// with the status endpoint enabled in the server configuration; use
+// ServerVersion and ParsedVersion.AtLeast("2.0") to check the version.
+func (c *Client) Status(ctx context.Context, opts ...RequestOption) (*ServerStatus, error) {
+	s := new(ServerStatus)
+	if err := c.callUnmarshal(ctx, "/status", s, opts); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStatus(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `{"server_id":"abc","status":"OPERATING","millis_since_last_parse_started":1500,"files_processed":42,"num_restarts":1}`)
+	}))
+	defer ts.Close()
+	got, err := NewClient(nil, ts.URL).Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status got error: %v", err)
+	}
+	want := ServerStatus{ServerID: "abc", Status: "OPERATING", MillisSinceLastParseStarted: 1500, FilesProcessed: 42, NumRestarts: 1}
+	if *got != want {
+		t.Errorf("Status got %+v, want %+v", *got, want)
+	}
+	if path != "/status" {
+		t.Errorf("Status requested %q, want %q", path, "/status")
+	}
+	if got, want := got.SinceLastParse(), 1500*time.Millisecond; got != want {
+		t.Errorf("SinceLastParse got %v, want %v", got, want)
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "invalid")
+	}))
+	defer ts.Close()
+	if _, err := NewClient(nil, ts.URL).Status(context.Background()); err == nil {
+		t.Errorf("Status got no error, want an error")
+	}
+	if _, err := errorClient.Status(context.Background()); err == nil {
+		t.Errorf("Status got no error, want an error")
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// A Supervisor runs a Server and restarts it whenever the underlying Java
+// process exits unexpectedly. Create a Supervisor with NewSupervisor and run
+// it with Run.
+type Supervisor struct {
+	s           *Server
+	maxRestarts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	mu        sync.Mutex
+	crashes   int
+	lastCrash error
+}
+
+// A SupervisorOption configures a Supervisor. See NewSupervisor.
+type SupervisorOption func(*Supervisor)
+
+// WithMaxRestarts sets the number of times the Supervisor restarts the
+// server before giving up. A negative n, the default, means the server is
+// restarted indefinitely.
+func WithMaxRestarts(n int) SupervisorOption {
+	return func(sv *Supervisor) {
+		sv.maxRestarts = n
+	}
+}
+
+// WithRestartBackoff sets how long the Supervisor waits before restarting a
+// crashed server. The wait starts at min and doubles after each consecutive
+// crash, up to max. The defaults are 1 second and 1 minute.
+func WithRestartBackoff(min, max time.Duration) SupervisorOption {
+	return func(sv *Supervisor) {
+		sv.minBackoff = min
+		sv.maxBackoff = max
+	}
+}
+
+// NewSupervisor creates a new Supervisor for s. s must not be started.
+func NewSupervisor(s *Server, opts ...SupervisorOption) *Supervisor {
+	sv := &Supervisor{
+		s:           s,
+		maxRestarts: -1,
+		minBackoff:  time.Second,
+		maxBackoff:  time.Minute,
+	}
+	for _, opt := range opts {
+		opt(sv)
+	}
+	return sv
+}
+
+// Crashes returns the number of times the server has exited unexpectedly or
+// failed to start.
+func (sv *Supervisor) Crashes() int {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.crashes
+}
+
+// LastCrash returns the error of the most recent crash, or nil if the server
+// has not crashed.
+func (sv *Supervisor) LastCrash() error {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	return sv.lastCrash
+}
+
+// crash records a crash and returns the total number of crashes.
+func (sv *Supervisor) crash(err error) int {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+	sv.crashes++
+	sv.lastCrash = err
+	return sv.crashes
+}
+
+// Run starts the server and blocks until ctx is done, restarting the server
+// whenever it exits. When ctx is done, Run stops the server and returns
+// ctx.Err(). Run returns an error if the server crashes more than the maximum
+// number of restarts.
+func (sv *Supervisor) Run(ctx context.Context) error {
+	backoff := sv.minBackoff
+	for {
+		started := time.Now()
+		err := sv.s.Start(ctx)
+		if ctx.Err() != nil {
+			if err == nil {
+				sv.s.Stop()
+			}
+			return ctx.Err()
+		}
+		if err == nil {
+			select {
+			case <-ctx.Done():
+				sv.s.Stop()
+				return ctx.Err()
+			case <-sv.s.exited:
+			}
+			err = sv.s.waitErr
+			if err == nil {
+				err = fmt.Errorf("server exited")
+			}
+		}
+		n := sv.crash(err)
+		logAttrs(ctx, sv.s.logger, slog.LevelWarn, "tika server crashed", slog.Int("crashes", n), slog.Any("error", err))
+		if sv.maxRestarts >= 0 && n > sv.maxRestarts {
+			return fmt.Errorf("server crashed %d times, giving up: %v", n, err)
+		}
+
+		// Only back off further if the server crashed soon after starting.
+		if time.Since(started) > sv.maxBackoff {
+			backoff = sv.minBackoff
+		}
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		logAttrs(ctx, sv.s.logger, slog.LevelInfo, "restarting tika server", slog.Int("crashes", n), slog.Duration("backoff", backoff))
+		if backoff *= 2; backoff > sv.maxBackoff {
+			backoff = sv.maxBackoff
+		}
+	}
+}
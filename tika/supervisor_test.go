@@ -0,0 +1,124 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSupervisorRestarts(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	// Simulate a server that crashes as soon as it starts.
+	defer func(old func(string, ...string) *exec.Cmd) { command = old }(command)
+	command = func(string, ...string) *exec.Cmd {
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "sleep", "0")
+		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		return c
+	}
+	ts := bouncyServer(0)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	s, err := NewServer(path, tsURL.Port())
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	sv := NewSupervisor(s, WithMaxRestarts(2), WithRestartBackoff(time.Millisecond, 10*time.Millisecond))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := sv.Run(ctx); err == nil || ctx.Err() != nil {
+		t.Fatalf("Run got %v, want a give up error", err)
+	}
+	if got, want := sv.Crashes(), 3; got != want {
+		t.Errorf("Crashes() = %d, want %d", got, want)
+	}
+	if sv.LastCrash() == nil {
+		t.Errorf("LastCrash() = nil, want an error")
+	}
+}
+
+func TestSupervisorStartFailure(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	// Simulate a server that runs but never becomes available.
+	defer func(old func(string, ...string) *exec.Cmd) { command = old }(command)
+	command = func(string, ...string) *exec.Cmd {
+		c := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", "sleep", "60")
+		c.Env = []string{"GO_WANT_HELPER_PROCESS=1"}
+		return c
+	}
+	ts := httptest.NewServer(http.NotFoundHandler())
+	port := strings.TrimPrefix(ts.URL, "http://127.0.0.1:")
+	ts.Close()
+	s, err := NewServer(path, port)
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	sv := NewSupervisor(s)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sv.Run(ctx); err != ctx.Err() {
+		t.Fatalf("Run got %v, want %v", err, ctx.Err())
+	}
+	select {
+	case <-s.exited:
+	default:
+		s.Stop()
+		t.Errorf("Run returned with the server process still running")
+	}
+}
+
+func TestSupervisorCancel(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	ts := bouncyServer(0)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	s, err := NewServer(path, tsURL.Port())
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	sv := NewSupervisor(s)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := sv.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Run got %v, want %v", err, context.DeadlineExceeded)
+	}
+	if got := sv.Crashes(); got != 0 {
+		t.Errorf("Crashes() = %d, want 0", got)
+	}
+}
@@ -17,11 +17,15 @@ limitations under the License.
 package tika
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"reflect"
 	"strings"
@@ -38,6 +42,39 @@ type Client struct {
 	// client is specified, a default client will be used. Since http.Clients are
 	// thread safe, the same client will be used for all requests by this Client.
 	httpClient *http.Client
+	// header is sent with every request, unless a request sets the same
+	// header itself.
+	header http.Header
+	// retry is the retry policy, or nil to never retry.
+	retry *RetryPolicy
+	// breaker is the circuit breaker, or nil if there is none.
+	breaker *breaker
+	// sem limits the number of concurrent requests, and limiter the rate of
+	// requests. They are nil if there is no limit.
+	sem     semaphore
+	limiter *limiter
+	// defaults are applied to every request before its own options.
+	defaults []RequestOption
+	// compress is set if request and response bodies are compressed.
+	compress bool
+	// middleware wraps every request.
+	middleware []Middleware
+	// tracer traces every request, metrics measures them and logger logs
+	// them. They are nil if unused.
+	tracer  Tracer
+	metrics Metrics
+	logger  *slog.Logger
+	// cache caches responses, if it is not nil.
+	cache *responseCache
+	// noDeadline is set if the deadline of a request isn't sent as its task
+	// timeout.
+	noDeadline bool
+	// maxUpload, if positive, is the maximum size of a document.
+	// truncateUpload is set if larger documents are truncated.
+	maxUpload      int64
+	truncateUpload bool
+	// normalize post-processes parsed text, if it is not nil.
+	normalize *TextNormalization
 }
 
 // NewClient creates a new Client. If httpClient is nil, the http.DefaultClient will be
@@ -46,6 +83,29 @@ func NewClient(httpClient *http.Client, urlString string) *Client {
 	return &Client{httpClient: httpClient, url: urlString}
 }
 
+// NewTLSClient creates a new Client that connects to an https urlString using
+// tlsConfig, for example to trust a private CA. See TLSConfigFromCAFile.
+func NewTLSClient(tlsConfig *tls.Config, urlString string) *Client {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = tlsConfig
+	return NewClient(&http.Client{Transport: t}, urlString)
+}
+
+// TLSConfigFromCAFile returns a *tls.Config that only trusts the PEM encoded
+// certificates in caFile. Use it with
+// NewTLSClient to connect to a Tika Server behind a private CA.
+func TLSConfigFromCAFile(caFile string) (*tls.Config, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
+
 // A Parser represents a Tika Parser. To get a list of all Parsers, see Parsers().
 type Parser struct {
 	Name           string
@@ -60,6 +120,8 @@ type Parser struct {
 type MIMEType struct {
 	Alias     []string
 	SuperType string
+	// Parser is the Java class name of the parser for the MIME Type, if any.
+	Parser string
 }
 
 // A Detector represents a Tika Detector. Detectors are used to get the filetype
@@ -74,7 +136,10 @@ type Detector struct {
 type Translator string
 
 // Translators available by defult in Tika. You must configure all required
-// authentication details in Tika Server (for example, an API key).
+// authentication details in Tika Server (for example, an API key). Each
+// translator reads them from a properties file on the server's classpath,
+// such as translator.google.properties for GoogleTranslator. Tika 2.x moved
+// the translators to a different package; see Translator.Tika2.
 const (
 	Lingo24Translator   Translator = "org.apache.tika.language.translate.Lingo24Translator"
 	GoogleTranslator    Translator = "org.apache.tika.language.translate.GoogleTranslator"
@@ -84,38 +149,178 @@ const (
 	YandexTranslator    Translator = "org.apache.tika.language.translate.YandexTranslator"
 )
 
+// Tika2 returns the name of t in Tika 2.x, which moved the default
+// translators to the org.apache.tika.language.translate.impl package. Other
+// translators are returned unchanged.
+func (t Translator) Tika2() Translator {
+	const pkg = "org.apache.tika.language.translate."
+	name := strings.TrimPrefix(string(t), pkg)
+	if name == string(t) || strings.Contains(name, ".") {
+		return t
+	}
+	return Translator(pkg + "impl." + name)
+}
+
 // XTIKAContent is the metadata field of the content of a file after recursive
 // parsing. See ParseRecursive and MetaRecursive.
 const XTIKAContent = "X-TIKA:content"
 
 // call makes the given request to c and returns the result as a []byte and
-// error. call returns an error if the response code is not 200 StatusOK.
-func (c *Client) call(ctx context.Context, input io.Reader, method, path string, header http.Header) ([]byte, error) {
-	if c.httpClient == nil {
-		c.httpClient = http.DefaultClient
-	}
-
-	req, err := http.NewRequest(method, c.url+path, input)
+// error. call returns an *Error if the response code is not 200 StatusOK.
+func (c *Client) call(ctx context.Context, input io.Reader, method, path string, header http.Header, opts []RequestOption) ([]byte, error) {
+	resp, err := c.do(ctx, input, method, path, header, opts)
 	if err != nil {
 		return nil, err
 	}
-	req.Header = header
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+// do makes the given request to c, configured by opts, and returns the
+// response, retrying it if c has a retry policy. do returns an *Error if the
+// response code is not 200 StatusOK. The caller must close the response body.
+func (c *Client) do(ctx context.Context, input io.Reader, method, path string, header http.Header, opts []RequestOption) (*http.Response, error) {
+	if c.tracer != nil || c.metrics != nil || c.logger != nil {
+		return c.instrumented(ctx, input, method, path, header, opts)
+	}
+	return c.doRequest(ctx, input, method, path, header, opts, nil)
+}
+
+// doRequest is do without tracing, metrics and logging. If stats is not nil, it is
+// updated as the request is sent.
+func (c *Client) doRequest(ctx context.Context, input io.Reader, method, path string, header http.Header, opts []RequestOption, stats *callStats) (*http.Response, error) {
+	if c.cache != nil {
+		return c.cached(ctx, input, method, path, header, opts, stats)
+	}
+	return c.sendRequest(ctx, input, method, path, header, opts, stats)
+}
+
+// sendRequest is doRequest without the cache.
+func (c *Client) sendRequest(ctx context.Context, input io.Reader, method, path string, header http.Header, opts []RequestOption, stats *callStats) (*http.Response, error) {
+	cfg := newRequestConfig(c.defaults, opts)
+	// Don't set c.httpClient, the Client may be used concurrently.
+	hc := c.httpClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	if cfg.noBody {
+		input = nil
+	}
+	form := false
+	if cfg.form && input != nil {
+		if p, ok := formPath(path); ok {
+			method, path, form = "POST", p, true
+		}
+	}
+	uploadSize := int64(-1)
+	if cfg.uploadProgress != nil && input != nil {
+		uploadSize = inputSize(input)
+		if c.maxUpload > 0 && c.truncateUpload && uploadSize > c.maxUpload {
+			uploadSize = c.maxUpload
+		}
+	}
+	if c.maxUpload > 0 && input != nil {
+		var err error
+		if input, err = c.limitUpload(input); err != nil {
+			return nil, err
+		}
+	}
 
-	resp, err := ctxhttp.Do(ctx, c.httpClient, req)
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, input)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("response code %v", resp.StatusCode)
+	req.Header = make(http.Header)
+	for k, v := range c.header {
+		req.Header[k] = v
 	}
-	return ioutil.ReadAll(resp.Body)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	for k, v := range cfg.header {
+		req.Header[k] = v
+	}
+	if c.retry != nil {
+		if err := c.retry.rewindable(req, input); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.uploadProgress != nil {
+		// Before compression, so progress is in bytes of the document.
+		trackUpload(req, uploadSize, cfg.uploadProgress)
+	}
+	if form {
+		if err := formBody(req); err != nil {
+			return nil, err
+		}
+	}
+	if c.compress {
+		req.Header.Set("Accept-Encoding", "gzip")
+		gzipRequest(req)
+	}
+
+	roundTrip := chain(func(req *http.Request) (*http.Response, error) {
+		if stats != nil && req.Body != nil {
+			req.Body = &countingBody{ReadCloser: req.Body, n: &stats.sent}
+		}
+		return ctxhttp.Do(ctx, hc, req)
+	}, c.middleware)
+	// An explicit task timeout is never replaced by the deadline.
+	propagate := !c.noDeadline && req.Header.Get(taskTimeoutHeader) == ""
+	send := func(req *http.Request) (*http.Response, error) {
+		if propagate {
+			// Set for every attempt, since retries have less time left.
+			setTaskTimeout(ctx, req)
+		}
+		resp, err := roundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if c.compress {
+			if err := gunzipResponse(resp); err != nil {
+				resp.Body.Close()
+				return nil, err
+			}
+		}
+		if resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+			return nil, &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		return resp, nil
+	}
+	if c.limiter != nil {
+		send = c.limiter.wrap(ctx, send)
+	}
+	if c.breaker != nil {
+		send = c.breaker.wrap(ctx, send)
+	}
+	if c.sem != nil {
+		send = c.sem.wrap(ctx, send)
+	}
+	if cfg.responseProgress != nil {
+		send = trackResponse(send, cfg.responseProgress)
+	}
+	if c.retry == nil {
+		return send(req)
+	}
+	var onRetry func()
+	if c.metrics != nil || c.logger != nil {
+		onRetry = func() {
+			ep := endpointOf(path)
+			if c.metrics != nil {
+				c.metrics.Retried(ep)
+			}
+			logAttrs(ctx, c.logger, slog.LevelDebug, "retrying tika request", slog.String("method", method), slog.String("endpoint", ep))
+		}
+	}
+	return c.retry.do(ctx, req, send, onRetry)
 }
 
 // callString makes the given request to c and returns the result as a string
 // and error. callString returns an error if the response code is not 200 StatusOK.
-func (c *Client) callString(ctx context.Context, input io.Reader, method, path string) (string, error) {
-	body, err := c.call(ctx, input, method, path, nil)
+func (c *Client) callString(ctx context.Context, input io.Reader, method, path string, opts []RequestOption) (string, error) {
+	body, err := c.call(ctx, input, method, path, nil, opts)
 	if err != nil {
 		return "", err
 	}
@@ -124,23 +329,95 @@ func (c *Client) callString(ctx context.Context, input io.Reader, method, path s
 
 // Parse parses the given input, returning the body of the input and an error.
 // If the error is not nil, the body is undefined.
-func (c *Client) Parse(ctx context.Context, input io.Reader) (string, error) {
-	return c.callString(ctx, input, "PUT", "/tika")
+func (c *Client) Parse(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	body, err := c.callString(ctx, input, "PUT", "/tika", opts)
+	return c.normalizeText(body), err
+}
+
+var (
+	plainHeader = http.Header{"Accept": []string{"text/plain"}}
+	xhtmlHeader = http.Header{"Accept": []string{"text/html"}}
+)
+
+// ParsePlain parses the given input, returning the body of the input as plain
+// text. If the error is not nil, the body is undefined.
+func (c *Client) ParsePlain(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	body, err := c.call(ctx, input, "PUT", "/tika", plainHeader, opts)
+	return c.normalizeText(string(body)), err
+}
+
+// ParseXHTML parses the given input, returning the body of the input as an
+// XHTML document. If the error is not nil, the body is undefined.
+func (c *Client) ParseXHTML(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	body, err := c.call(ctx, input, "PUT", "/tika", xhtmlHeader, opts)
+	return string(body), err
+}
+
+// ParseReader parses the given input, returning a reader of the body of the
+// input. Unlike Parse, the body isn't held in memory, so it's suitable for
+// large documents. The caller must close the reader.
+func (c *Client) ParseReader(ctx context.Context, input io.Reader, opts ...RequestOption) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, input, "PUT", "/tika", nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// ParseTo parses the given input and writes the body of the input to w,
+// returning the number of bytes written. If the error is not nil, w may have
+// been written a partial body.
+func (c *Client) ParseTo(ctx context.Context, w io.Writer, input io.Reader, opts ...RequestOption) (int64, error) {
+	r, err := c.ParseReader(ctx, input, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	return io.Copy(w, r)
+}
+
+// ParseLimited is like Parse, but returns at most limit characters of the
+// body. truncated reports whether the body was longer than limit. The limit
+// is also sent to the Tika Server (see WithWriteLimit), so that servers which
+// support it stop extracting text early.
+func (c *Client) ParseLimited(ctx context.Context, input io.Reader, limit int, opts ...RequestOption) (body string, truncated bool, err error) {
+	opts = append([]RequestOption{WithWriteLimit(limit)}, opts...)
+	resp, err := c.do(ctx, input, "PUT", "/tika", nil, opts)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	r := bufio.NewReader(resp.Body)
+	var b strings.Builder
+	for n := 0; n < limit; n++ {
+		ch, _, err := r.ReadRune()
+		if err == io.EOF {
+			return c.normalizeText(b.String()), false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		b.WriteRune(ch)
+	}
+	if _, _, err := r.ReadRune(); err == io.EOF {
+		return c.normalizeText(b.String()), false, nil
+	}
+	return c.normalizeText(b.String()), true, nil
 }
 
 // ParseRecursive parses the given input and all embedded documents, returning a
 // list of the contents of the input with one element per document. See
 // MetaRecursive for access to all metadata fields. If the error is not nil, the
 // result is undefined.
-func (c *Client) ParseRecursive(ctx context.Context, input io.Reader) ([]string, error) {
-	m, err := c.MetaRecursive(ctx, input)
+func (c *Client) ParseRecursive(ctx context.Context, input io.Reader, opts ...RequestOption) ([]string, error) {
+	m, err := c.MetaRecursive(ctx, input, opts...)
 	if err != nil {
 		return nil, err
 	}
 	var r []string
 	for _, d := range m {
 		if content := d[XTIKAContent]; len(content) > 0 {
-			r = append(r, content[0])
+			r = append(r, c.normalizeText(content[0]))
 		}
 	}
 	return r, nil
@@ -148,35 +425,89 @@ func (c *Client) ParseRecursive(ctx context.Context, input io.Reader) ([]string,
 
 // Meta parses the metadata from the given input, returning the metadata and an
 // error. If the error is not nil, the metadata is undefined.
-func (c *Client) Meta(ctx context.Context, input io.Reader) (string, error) {
-	return c.callString(ctx, input, "PUT", "/meta")
+func (c *Client) Meta(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	return c.callString(ctx, input, "PUT", "/meta", opts)
+}
+
+// MetaJSON parses the metadata from the given input, returning the metadata
+// and an error. Unlike Meta, the metadata is requested as JSON, so values
+// containing commas and quotes are returned intact. Use Meta for servers that
+// can't return JSON metadata. If the error is not nil, the metadata is
+// undefined.
+func (c *Client) MetaJSON(ctx context.Context, input io.Reader, opts ...RequestOption) (Metadata, error) {
+	body, err := c.call(ctx, input, "PUT", "/meta", jsonHeader, opts)
+	if err != nil {
+		return nil, err
+	}
+	var d map[string]interface{}
+	if err := json.Unmarshal(body, &d); err != nil {
+		return nil, err
+	}
+	return decodeMetadata(d)
 }
 
 // MetaField parses the metadata from the given input and returns the given
 // field. If the error is not nil, the result string is undefined.
-func (c *Client) MetaField(ctx context.Context, input io.Reader, field string) (string, error) {
-	return c.callString(ctx, input, "PUT", fmt.Sprintf("/meta/%v", field))
+func (c *Client) MetaField(ctx context.Context, input io.Reader, field string, opts ...RequestOption) (string, error) {
+	return c.callString(ctx, input, "PUT", fmt.Sprintf("/meta/%v", field), opts)
+}
+
+// MetaFieldValues parses the metadata from the given input and returns all
+// values of the given field, such as the authors in MetaCreator. The field is
+// requested as JSON, so values containing commas are returned intact. If the
+// error is not nil, the result is undefined.
+func (c *Client) MetaFieldValues(ctx context.Context, input io.Reader, field string, opts ...RequestOption) ([]string, error) {
+	body, err := c.call(ctx, input, "PUT", fmt.Sprintf("/meta/%v", field), jsonHeader, opts)
+	if err != nil {
+		return nil, err
+	}
+	var d map[string]interface{}
+	if err := json.Unmarshal(body, &d); err != nil {
+		return nil, err
+	}
+	m, err := decodeMetadata(d)
+	if err != nil {
+		return nil, err
+	}
+	return m[field], nil
 }
 
 // Detect gets the mimetype of the given input, returning the mimetype and an
 // error. If the error is not nil, the mimetype is undefined.
-func (c *Client) Detect(ctx context.Context, input io.Reader) (string, error) {
-	return c.callString(ctx, input, "PUT", "/detect/stream")
+func (c *Client) Detect(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	return c.callString(ctx, input, "PUT", "/detect/stream", opts)
 }
 
-// Language detects the language of the given input, returning the two letter
-// language code and an error. If the error is not nil, the language is
-// undefined.
-func (c *Client) Language(ctx context.Context, input io.Reader) (string, error) {
-	return c.callString(ctx, input, "PUT", "/language/stream")
+// Language detects the language of the given input using /language/stream,
+// returning the language code and an error. The code is an ISO 639-1 code,
+// such as "en", optionally followed by a region, as in "zh-CN", for languages
+// Tika distinguishes by region; see normalizeLanguage. If the error is not
+// nil, the language is undefined.
+func (c *Client) Language(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	lang, err := c.callString(ctx, input, "PUT", "/language/stream", opts)
+	return normalizeLanguage(lang), err
 }
 
-// LanguageString detects the language of the given string, returning the two letter
-// language code and an error. If the error is not nil, the language is
-// undefined.
-func (c *Client) LanguageString(ctx context.Context, input string) (string, error) {
+// LanguageString detects the language of the given string using
+// /language/string, returning the language code, as for Language, and an
+// error. Use it rather than Language for text that is already in memory. Tika
+// Server doesn't report how certain the detection is, so short strings may be
+// misdetected. If the error is not nil, the language is undefined.
+func (c *Client) LanguageString(ctx context.Context, input string, opts ...RequestOption) (string, error) {
 	r := strings.NewReader(input)
-	return c.callString(ctx, r, "PUT", "/language/string")
+	lang, err := c.callString(ctx, r, "PUT", "/language/string", opts)
+	return normalizeLanguage(lang), err
+}
+
+// normalizeLanguage normalizes a language code returned by Tika Server, which
+// varies between versions and detectors, to a lower case ISO 639-1 code with
+// an optional upper case region, as in "en" or "zh-CN".
+func normalizeLanguage(lang string) string {
+	lang = strings.Replace(strings.TrimSpace(lang), "_", "-", -1)
+	if i := strings.Index(lang, "-"); i >= 0 {
+		return strings.ToLower(lang[:i]) + "-" + strings.ToUpper(lang[i+1:])
+	}
+	return strings.ToLower(lang)
 }
 
 // MetaRecursive parses the given input and all embedded documents. The result
@@ -184,8 +515,8 @@ func (c *Client) LanguageString(ctx context.Context, input string) (string, erro
 // of each document is in the XTIKAContent field in text form. See
 // ParseRecursive to just get the content of each document. If the error is not
 // nil, the result list is undefined.
-func (c *Client) MetaRecursive(ctx context.Context, input io.Reader) ([]map[string][]string, error) {
-	return c.MetaRecursiveType(ctx, input, "text")
+func (c *Client) MetaRecursive(ctx context.Context, input io.Reader, opts ...RequestOption) ([]map[string][]string, error) {
+	return c.MetaRecursiveType(ctx, input, "text", opts...)
 }
 
 // MetaRecursiveType parses the given input and all embedded documents. The result
@@ -194,12 +525,12 @@ func (c *Client) MetaRecursive(ctx context.Context, input io.Reader) ([]map[stri
 // by the contentType parameter An empty string can be passed in for a default
 // type of XML. See ParseRecursive to just get the content of each document. If
 // the error is not nil, the result list is undefined.
-func (c *Client) MetaRecursiveType(ctx context.Context, input io.Reader, contentType string) ([]map[string][]string, error) {
+func (c *Client) MetaRecursiveType(ctx context.Context, input io.Reader, contentType string, opts ...RequestOption) ([]map[string][]string, error) {
 	path := "/rmeta"
 	if contentType != "" {
 		path = fmt.Sprintf("/rmeta/%s", contentType)
 	}
-	body, err := c.call(ctx, input, "PUT", path, nil)
+	body, err := c.call(ctx, input, "PUT", path, nil, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -209,44 +540,62 @@ func (c *Client) MetaRecursiveType(ctx context.Context, input io.Reader, content
 	}
 	var r []map[string][]string
 	for _, d := range m {
-		doc := make(map[string][]string)
+		doc, err := decodeMetadata(d)
+		if err != nil {
+			return nil, err
+		}
 		r = append(r, doc)
-		for k, v := range d {
-			switch vt := v.(type) {
-			case string:
-				doc[k] = []string{vt}
-			case []interface{}:
-				for _, i := range vt {
-					s, ok := i.(string)
-					if !ok {
-						return nil, fmt.Errorf("field %q has value %v and type %T, expected a string or []string", k, v, vt)
-					}
-					doc[k] = append(doc[k], s)
+	}
+	return r, nil
+}
+
+// decodeMetadata converts the JSON metadata of a document, where each value
+// is a string or a list of strings, to a Metadata.
+func decodeMetadata(d map[string]interface{}) (Metadata, error) {
+	doc := make(Metadata)
+	for k, v := range d {
+		switch vt := v.(type) {
+		case string:
+			doc[k] = []string{vt}
+		case []interface{}:
+			for _, i := range vt {
+				s, ok := i.(string)
+				if !ok {
+					return nil, fmt.Errorf("field %q has value %v and type %T, expected a string or []string", k, v, vt)
 				}
-			default:
-				return nil, fmt.Errorf("field %q has value %v and type %v, expected a string or []string", k, v, reflect.TypeOf(v))
+				doc[k] = append(doc[k], s)
 			}
+		default:
+			return nil, fmt.Errorf("field %q has value %v and type %v, expected a string or []string", k, v, reflect.TypeOf(v))
 		}
 	}
-	return r, nil
+	return doc, nil
 }
 
 // Translate returns an error and the translated input from src language to
 // dst language using t. If the error is not nil, the translation is undefined.
-func (c *Client) Translate(ctx context.Context, input io.Reader, t Translator, src, dst string) (string, error) {
-	return c.callString(ctx, input, "POST", fmt.Sprintf("/translate/all/%s/%s/%s", t, src, dst))
+func (c *Client) Translate(ctx context.Context, input io.Reader, t Translator, src, dst string, opts ...RequestOption) (string, error) {
+	return c.callString(ctx, input, "POST", fmt.Sprintf("/translate/all/%s/%s/%s", t, src, dst), opts)
+}
+
+// TranslateAuto is like Translate, but Tika detects the language of the input
+// before translating it, using /translate/all/{translator}/{dst}. Use it when
+// the language of the input isn't known; dst is an ISO 639-1 code, such as
+// "fr". See Language to detect the language without translating.
+func (c *Client) TranslateAuto(ctx context.Context, input io.Reader, t Translator, dst string, opts ...RequestOption) (string, error) {
+	return c.callString(ctx, input, "POST", fmt.Sprintf("/translate/all/%s/%s", t, dst), opts)
 }
 
 // Version returns the default hello message from Tika server.
-func (c *Client) Version(ctx context.Context) (string, error) {
-	return c.callString(ctx, nil, "GET", "/version")
+func (c *Client) Version(ctx context.Context, opts ...RequestOption) (string, error) {
+	return c.callString(ctx, nil, "GET", "/version", opts)
 }
 
 var jsonHeader = http.Header{"Accept": []string{"application/json"}}
 
 // callUnmarshal is like call, but unmarshals the JSON response into v.
-func (c *Client) callUnmarshal(ctx context.Context, path string, v interface{}) error {
-	body, err := c.call(ctx, nil, "GET", path, jsonHeader)
+func (c *Client) callUnmarshal(ctx context.Context, path string, v interface{}, opts []RequestOption) error {
+	body, err := c.call(ctx, nil, "GET", path, jsonHeader, opts)
 	if err != nil {
 		return err
 	}
@@ -256,19 +605,19 @@ func (c *Client) callUnmarshal(ctx context.Context, path string, v interface{})
 // Parsers returns the list of available parsers and an error. If the error is
 // not nil, the list is undefined. To get all available parsers, iterate through
 // the Children of every Parser.
-func (c *Client) Parsers(ctx context.Context) (*Parser, error) {
+func (c *Client) Parsers(ctx context.Context, opts ...RequestOption) (*Parser, error) {
 	p := new(Parser)
-	if err := c.callUnmarshal(ctx, "/parsers/details", p); err != nil {
+	if err := c.callUnmarshal(ctx, "/parsers/details", p, opts); err != nil {
 		return nil, err
 	}
 	return p, nil
 }
 
 // MIMETypes returns a map from MIME Type name to MIMEType, or properties about
-// that specific MIMEType.
-func (c *Client) MIMETypes(ctx context.Context) (map[string]MIMEType, error) {
+// that specific MIMEType. See MIMETypeMap for lookups.
+func (c *Client) MIMETypes(ctx context.Context, opts ...RequestOption) (map[string]MIMEType, error) {
 	mt := make(map[string]MIMEType)
-	if err := c.callUnmarshal(ctx, "/mime-types", &mt); err != nil {
+	if err := c.callUnmarshal(ctx, "/mime-types", &mt, opts); err != nil {
 		return nil, err
 	}
 	return mt, nil
@@ -276,9 +625,9 @@ func (c *Client) MIMETypes(ctx context.Context) (map[string]MIMEType, error) {
 
 // Detectors returns the list of available Detectors for this server. To get all
 // available detectors, iterate through the Children of every Detector.
-func (c *Client) Detectors(ctx context.Context) (*Detector, error) {
+func (c *Client) Detectors(ctx context.Context, opts ...RequestOption) (*Detector, error) {
 	d := new(Detector)
-	if err := c.callUnmarshal(ctx, "/detectors", d); err != nil {
+	if err := c.callUnmarshal(ctx, "/detectors", d, opts); err != nil {
 		return nil, err
 	}
 	return d, nil
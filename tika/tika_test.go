@@ -17,9 +17,13 @@ limitations under the License.
 package tika
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -50,13 +54,60 @@ func TestCallError(t *testing.T) {
 	}
 	for _, test := range tests {
 		c := NewClient(nil, test.url)
-		if _, err := c.call(context.Background(), nil, test.method, "", nil); err == nil {
+		if _, err := c.call(context.Background(), nil, test.method, "", nil, nil); err == nil {
 			t.Errorf("call(%q, %q) got no error, want error", test.method, test.url)
 		}
 
 	}
 }
 
+func TestNewTLSClient(t *testing.T) {
+	want := "test value"
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+
+	f, err := ioutil.TempFile("", "ca*.pem")
+	if err != nil {
+		t.Fatalf("error creating CA file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if err := pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}); err != nil {
+		t.Fatalf("error writing CA file: %v", err)
+	}
+	f.Close()
+
+	if _, err := NewClient(nil, ts.URL).Version(context.Background()); err == nil {
+		t.Errorf("Version with an untrusted certificate got no error, want an error")
+	}
+	cfg, err := TLSConfigFromCAFile(f.Name())
+	if err != nil {
+		t.Fatalf("TLSConfigFromCAFile got error: %v", err)
+	}
+	got, err := NewTLSClient(cfg, ts.URL).Version(context.Background())
+	if err != nil {
+		t.Fatalf("Version got error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Version got %q, want %q", got, want)
+	}
+}
+
+func TestTLSConfigFromCAFileError(t *testing.T) {
+	f, err := ioutil.TempFile("", "ca*.pem")
+	if err != nil {
+		t.Fatalf("error creating CA file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	for _, path := range []string{"path_to_non_existent_file", f.Name()} {
+		if _, err := TLSConfigFromCAFile(path); err == nil {
+			t.Errorf("TLSConfigFromCAFile(%q) got no error, want an error", path)
+		}
+	}
+}
+
 func TestParse(t *testing.T) {
 	want := "test value"
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -73,6 +124,108 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func TestParseAccept(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Accept"))
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	tests := []struct {
+		name  string
+		parse func(context.Context, io.Reader, ...RequestOption) (string, error)
+		want  string
+	}{
+		{"ParsePlain", c.ParsePlain, "text/plain"},
+		{"ParseXHTML", c.ParseXHTML, "text/html"},
+	}
+	for _, test := range tests {
+		got, err := test.parse(context.Background(), nil)
+		if err != nil {
+			t.Errorf("%s got error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s sent Accept %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestParseReader(t *testing.T) {
+	want := "test value"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	r, err := c.ParseReader(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ParseReader got error: %v", err)
+	}
+	defer r.Close()
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("error reading ParseReader body: %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("ParseReader got %q, want %q", got, want)
+	}
+	if _, err := errorClient.ParseReader(context.Background(), nil); err == nil {
+		t.Errorf("ParseReader got no error, want an error")
+	}
+}
+
+func TestParseTo(t *testing.T) {
+	want := "test value"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	var b bytes.Buffer
+	n, err := c.ParseTo(context.Background(), &b, nil)
+	if err != nil {
+		t.Fatalf("ParseTo got error: %v", err)
+	}
+	if got := b.String(); got != want || n != int64(len(want)) {
+		t.Errorf("ParseTo got (%q, %d), want (%q, %d)", got, n, want, len(want))
+	}
+	if _, err := errorClient.ParseTo(context.Background(), &b, nil); err == nil {
+		t.Errorf("ParseTo got no error, want an error")
+	}
+}
+
+func TestParseLimited(t *testing.T) {
+	var limit string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit = r.Header.Get("writeLimit")
+		fmt.Fprint(w, "héllo")
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	tests := []struct {
+		limit         int
+		want          string
+		wantTruncated bool
+	}{
+		{limit: 2, want: "hé", wantTruncated: true},
+		{limit: 5, want: "héllo"},
+		{limit: 10, want: "héllo"},
+	}
+	for _, test := range tests {
+		got, truncated, err := c.ParseLimited(context.Background(), nil, test.limit)
+		if err != nil {
+			t.Errorf("ParseLimited(%d) got error: %v", test.limit, err)
+			continue
+		}
+		if got != test.want || truncated != test.wantTruncated {
+			t.Errorf("ParseLimited(%d) got (%q, %v), want (%q, %v)", test.limit, got, truncated, test.want, test.wantTruncated)
+		}
+		if want := fmt.Sprint(test.limit); limit != want {
+			t.Errorf("ParseLimited(%d) sent writeLimit %q, want %q", test.limit, limit, want)
+		}
+	}
+}
+
 func TestParseRecursive(t *testing.T) {
 	tests := []struct {
 		response string
@@ -133,6 +286,50 @@ func TestMeta(t *testing.T) {
 	}
 }
 
+func TestMetaJSON(t *testing.T) {
+	var accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		fmt.Fprint(w, `{"dc:title":"a, \"quoted\" title","dc:creator":["Alice","Bob"]}`)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	got, err := c.MetaJSON(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("MetaJSON got error: %v", err)
+	}
+	want := Metadata{"dc:title": {`a, "quoted" title`}, "dc:creator": {"Alice", "Bob"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MetaJSON got %v, want %v", got, want)
+	}
+	if accept != "application/json" {
+		t.Errorf("MetaJSON sent Accept %q, want %q", accept, "application/json")
+	}
+}
+
+func TestMetaJSONError(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"bad JSON", "not JSON"},
+		{"bad field type", `{"number":1}`},
+	}
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, test.response)
+		}))
+		c := NewClient(nil, ts.URL)
+		if _, err := c.MetaJSON(context.Background(), nil); err == nil {
+			t.Errorf("MetaJSON with %s got no error, want an error", test.name)
+		}
+		ts.Close()
+	}
+	if _, err := errorClient.MetaJSON(context.Background(), nil); err == nil {
+		t.Errorf("MetaJSON got no error, want an error")
+	}
+}
+
 func TestMetaField(t *testing.T) {
 	want := "test value"
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -181,20 +378,87 @@ func TestLanguage(t *testing.T) {
 	}
 }
 
+func TestLanguageNormalized(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"en", "en"},
+		{"FR\n", "fr"},
+		{"zh-cn", "zh-CN"},
+		{"zh_TW", "zh-TW"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, test.body)
+		}))
+		c := NewClient(nil, ts.URL)
+		if got, err := c.Language(context.Background(), nil); err != nil || got != test.want {
+			t.Errorf("Language with response %q got (%q, %v), want (%q, nil)", test.body, got, err, test.want)
+		}
+		if got, err := c.LanguageString(context.Background(), "text"); err != nil || got != test.want {
+			t.Errorf("LanguageString with response %q got (%q, %v), want (%q, nil)", test.body, got, err, test.want)
+		}
+		ts.Close()
+	}
+}
+
 func TestLanguageString(t *testing.T) {
 	want := "test value"
-	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	input := "bonjour tout le monde"
+	var path, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		path, body = r.URL.Path, string(b)
 		fmt.Fprint(w, want)
 	}))
 	defer ts.Close()
 	c := NewClient(nil, ts.URL)
-	got, err := c.LanguageString(context.Background(), "")
+	got, err := c.LanguageString(context.Background(), input)
 	if err != nil {
 		t.Errorf("LanguageString returned an error: %v, want %q", err, want)
 	}
 	if got != want {
 		t.Errorf("LanguageString got %q, want %q", got, want)
 	}
+	if path != "/language/string" || body != input {
+		t.Errorf("LanguageString sent %q to %q, want %q to %q", body, path, input, "/language/string")
+	}
+}
+
+func TestMetaFieldValues(t *testing.T) {
+	tests := []struct {
+		response string
+		want     []string
+	}{
+		{`{"dc:creator":"Alice"}`, []string{"Alice"}},
+		{`{"dc:creator":["Alice","Bob, Jr."]}`, []string{"Alice", "Bob, Jr."}},
+		{`{}`, nil},
+	}
+	for _, test := range tests {
+		var path string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			fmt.Fprint(w, test.response)
+		}))
+		c := NewClient(nil, ts.URL)
+		got, err := c.MetaFieldValues(context.Background(), nil, MetaCreator)
+		ts.Close()
+		if err != nil {
+			t.Errorf("MetaFieldValues with response %s got error: %v", test.response, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("MetaFieldValues with response %s got %q, want %q", test.response, got, test.want)
+		}
+		if want := "/meta/" + MetaCreator; path != want {
+			t.Errorf("MetaFieldValues requested %q, want %q", path, want)
+		}
+	}
+	if _, err := errorClient.MetaFieldValues(context.Background(), nil, MetaCreator); err == nil {
+		t.Errorf("MetaFieldValues got no error, want an error")
+	}
 }
 
 func TestMetaRecursive(t *testing.T) {
@@ -359,6 +623,43 @@ func TestTranslate(t *testing.T) {
 	}
 }
 
+func TestTranslateAuto(t *testing.T) {
+	want := "test value"
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, want)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	got, err := c.TranslateAuto(context.Background(), nil, GoogleTranslator, "fr")
+	if err != nil {
+		t.Fatalf("TranslateAuto returned an error: %v, want %q", err, want)
+	}
+	if got != want {
+		t.Errorf("TranslateAuto got %q, want %q", got, want)
+	}
+	if wantPath := "/translate/all/" + string(GoogleTranslator) + "/fr"; path != wantPath {
+		t.Errorf("TranslateAuto requested %q, want %q", path, wantPath)
+	}
+}
+
+func TestTranslatorTika2(t *testing.T) {
+	tests := []struct {
+		t    Translator
+		want Translator
+	}{
+		{GoogleTranslator, "org.apache.tika.language.translate.impl.GoogleTranslator"},
+		{"org.apache.tika.language.translate.impl.GoogleTranslator", "org.apache.tika.language.translate.impl.GoogleTranslator"},
+		{"com.example.MyTranslator", "com.example.MyTranslator"},
+	}
+	for _, test := range tests {
+		if got := test.t.Tika2(); got != test.want {
+			t.Errorf("%q.Tika2() got %q, want %q", test.t, got, test.want)
+		}
+	}
+}
+
 func TestParsers(t *testing.T) {
 	tests := []struct {
 		response string
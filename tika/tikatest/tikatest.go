@@ -0,0 +1,361 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tikatest provides a fake, in-process Tika Server for testing code
+// that uses the tika package without Java or a real server.
+//
+//	s := tikatest.NewServer()
+//	defer s.Close()
+//	s.SetDocument(tikatest.Document{
+//		Text:     "Hello, world",
+//		Metadata: map[string][]string{"Content-Type": {"application/pdf"}},
+//	})
+//	c := s.Client()
+//	text, err := c.Parse(ctx, strings.NewReader("%PDF-1.4"))
+//
+// The fake implements /tika, /meta, /rmeta, /detect and /version. Responses
+// can be replaced with Handle, and failures injected with Fail.
+package tikatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-tika/tika"
+)
+
+// DefaultVersion is the version string the Server reports by default.
+const DefaultVersion = "Apache Tika 2.9.2"
+
+// A Document is the canned result of parsing a document.
+type Document struct {
+	// Text is the content of the document.
+	Text string
+	// Metadata is the metadata of the document. Its Content-Type is returned
+	// by /detect.
+	Metadata map[string][]string
+	// Embedded are the documents embedded in the document. They are only
+	// returned by /rmeta.
+	Embedded []Document
+}
+
+// A Response is a canned HTTP response.
+type Response struct {
+	// StatusCode is the status of the response. The default is 200 OK.
+	StatusCode int
+	// Header is added to the response headers.
+	Header http.Header
+	// Body is the response body.
+	Body string
+	// Delay is how long to wait before responding. The wait ends early if the
+	// client cancels the request.
+	Delay time.Duration
+}
+
+// A Request is a request the Server received.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake Tika Server. Create one with NewServer. It is safe to
+// configure the Server while it handles requests.
+type Server struct {
+	// URL is the base URL of the server, for use with tika.NewClient.
+	URL string
+
+	ts *httptest.Server
+
+	mu        sync.Mutex
+	doc       *Document
+	version   string
+	responses map[string]Response
+	failures  []failure
+	requests  []Request
+}
+
+// failure is an injected failure of the next n requests to path.
+type failure struct {
+	path string
+	n    int
+	resp Response
+}
+
+// NewServer starts and returns a new Server. The caller must call Close when
+// finished with it.
+//
+// Until SetDocument is called, the Server returns the request body as the
+// text of every document, with the Content-Type text/plain.
+func NewServer() *Server {
+	s := &Server{
+		version:   DefaultVersion,
+		responses: make(map[string]Response),
+	}
+	s.ts = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.ts.URL
+	return s
+}
+
+// Close shuts the Server down and blocks until all outstanding requests have
+// completed.
+func (s *Server) Close() {
+	s.ts.Close()
+}
+
+// Client returns a tika.Client for the Server, configured by opts.
+func (s *Server) Client(opts ...tika.ClientOption) *tika.Client {
+	opts = append([]tika.ClientOption{tika.WithHTTPClient(s.ts.Client())}, opts...)
+	return tika.NewClientWithOptions(s.URL, opts...)
+}
+
+// SetDocument sets the document every request parses to.
+func (s *Server) SetDocument(d Document) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc = &d
+}
+
+// SetVersion sets the version string returned by /version.
+func (s *Server) SetVersion(v string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = v
+}
+
+// Handle responds to every request to path with r, instead of the response
+// derived from the document. Paths are matched exactly, for example
+// "/meta/Content-Type" or "/parsers/details".
+func (s *Server) Handle(path string, r Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[path] = r
+}
+
+// Fail responds to the next n requests with r. If path is not empty, only
+// requests to path, or to paths starting with path + "/", fail. Failures
+// are used up in the order they were added. If r.StatusCode is 0, it
+// defaults to 500 Internal Server Error.
+func (s *Server) Fail(path string, n int, r Response) {
+	if r.StatusCode == 0 {
+		r.StatusCode = http.StatusInternalServerError
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{path: path, n: n, resp: r})
+}
+
+// Requests returns the requests the Server has received, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset forgets the recorded requests, canned responses and pending
+// failures, and restores the default document and version.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc = nil
+	s.version = DefaultVersion
+	s.responses = make(map[string]Response)
+	s.failures = nil
+	s.requests = nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	resp, ok := s.response(r, body)
+	if !ok {
+		resp = s.serveTika(r, body)
+	}
+	if resp.Delay > 0 {
+		t := time.NewTimer(resp.Delay)
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			t.Stop()
+			return
+		}
+	}
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	if resp.StatusCode != 0 {
+		w.WriteHeader(resp.StatusCode)
+	}
+	fmt.Fprint(w, resp.Body)
+}
+
+// response records the request and returns an injected failure or canned
+// response for it, if there is one.
+func (s *Server) response(r *http.Request, body []byte) (Response, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Header: r.Header,
+		Body:   body,
+	})
+	for i := range s.failures {
+		f := &s.failures[i]
+		if f.n <= 0 || !matchPath(f.path, r.URL.Path) {
+			continue
+		}
+		f.n--
+		return f.resp, true
+	}
+	resp, ok := s.responses[r.URL.Path]
+	return resp, ok
+}
+
+// matchPath reports whether path is prefix, or below it.
+func matchPath(prefix, path string) bool {
+	return prefix == "" || path == prefix || strings.HasPrefix(path, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+// document returns the document to respond with for the given request body.
+func (s *Server) document(body []byte) Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.doc != nil {
+		return *s.doc
+	}
+	return Document{
+		Text:     string(body),
+		Metadata: map[string][]string{"Content-Type": {"text/plain"}},
+	}
+}
+
+// serveTika responds to r like Tika Server would for the current document.
+func (s *Server) serveTika(r *http.Request, body []byte) Response {
+	path := r.URL.Path
+	d := s.document(body)
+	asJSON := strings.Contains(r.Header.Get("Accept"), "json")
+	switch {
+	case path == "/version" && r.Method == "GET":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return Response{Body: s.version}
+	case path == "/tika" && (r.Method == "GET" || r.Method == "HEAD"):
+		return Response{Body: "This is Tika Server. Please PUT\n"}
+	case path == "/tika" && r.Method == "PUT":
+		return Response{Body: d.Text}
+	case path == "/meta" && r.Method == "PUT":
+		if asJSON {
+			return jsonResponse(metadataJSON(d.Metadata))
+		}
+		return Response{Body: metadataCSV(d.Metadata)}
+	case strings.HasPrefix(path, "/meta/") && r.Method == "PUT":
+		field := strings.TrimPrefix(path, "/meta/")
+		v, ok := d.Metadata[field]
+		if !ok {
+			return Response{StatusCode: http.StatusNotFound}
+		}
+		m := map[string][]string{field: v}
+		if asJSON {
+			return jsonResponse(metadataJSON(m))
+		}
+		return Response{Body: metadataCSV(m)}
+	case (path == "/rmeta" || strings.HasPrefix(path, "/rmeta/")) && r.Method == "PUT":
+		format := strings.TrimPrefix(strings.TrimPrefix(path, "/rmeta"), "/")
+		return jsonResponse(recursiveJSON(nil, d, format))
+	case path == "/detect/stream" && r.Method == "PUT":
+		if v := d.Metadata["Content-Type"]; len(v) > 0 {
+			return Response{Body: v[0]}
+		}
+		return Response{Body: "application/octet-stream"}
+	}
+	return Response{StatusCode: http.StatusNotFound}
+}
+
+// jsonResponse returns a Response with v encoded as JSON.
+func jsonResponse(v interface{}) Response {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return Response{StatusCode: http.StatusInternalServerError, Body: err.Error()}
+	}
+	return Response{
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   string(b),
+	}
+}
+
+// metadataJSON returns m in the form Tika Server uses in JSON: single values
+// are strings and multiple values are arrays.
+func metadataJSON(m map[string][]string) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if len(v) == 1 {
+			out[k] = v[0]
+		} else {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// metadataCSV returns m in the CSV form Tika Server uses for text/plain, with
+// one line per key, sorted by key.
+func metadataCSV(m map[string][]string) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(quote(k))
+		for _, v := range m[k] {
+			b.WriteString(",")
+			b.WriteString(quote(v))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func quote(s string) string {
+	return `"` + strings.Replace(s, `"`, `""`, -1) + `"`
+}
+
+// recursiveJSON appends d and its embedded documents to docs, in the form
+// /rmeta returns them.
+func recursiveJSON(docs []map[string]interface{}, d Document, format string) []map[string]interface{} {
+	m := metadataJSON(d.Metadata)
+	if format != "ignore" {
+		m[tika.XTIKAContent] = d.Text
+	}
+	docs = append(docs, m)
+	for _, e := range d.Embedded {
+		docs = recursiveJSON(docs, e, format)
+	}
+	return docs
+}
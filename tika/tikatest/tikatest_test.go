@@ -0,0 +1,175 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tikatest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-tika/tika"
+)
+
+func TestDefaults(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	ctx := context.Background()
+
+	if got, err := c.Parse(ctx, strings.NewReader("echo")); err != nil || got != "echo" {
+		t.Errorf("Parse = %q, %v, want %q", got, err, "echo")
+	}
+	if got, err := c.Version(ctx); err != nil || got != DefaultVersion {
+		t.Errorf("Version = %q, %v, want %q", got, err, DefaultVersion)
+	}
+	if !c.IsAlive(ctx) {
+		t.Errorf("IsAlive = false, want true")
+	}
+	if got, err := c.Detect(ctx, strings.NewReader("echo")); err != nil || got != "text/plain" {
+		t.Errorf("Detect = %q, %v, want %q", got, err, "text/plain")
+	}
+	if _, err := c.Translate(ctx, strings.NewReader("echo"), tika.Lingo24Translator, "en", "fr"); err == nil {
+		t.Errorf("Translate got no error, want 404")
+	}
+}
+
+func TestSetDocument(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetDocument(Document{
+		Text: "container",
+		Metadata: map[string][]string{
+			"Content-Type": {"application/zip"},
+			"dc:creator":   {"Alice, Jr.", "Bob"},
+		},
+		Embedded: []Document{{Text: "embedded", Metadata: map[string][]string{"Content-Type": {"text/plain"}}}},
+	})
+	s.SetVersion("Apache Tika 1.21")
+	c := s.Client()
+	ctx := context.Background()
+
+	if got, err := c.Parse(ctx, strings.NewReader("input")); err != nil || got != "container" {
+		t.Errorf("Parse = %q, %v, want %q", got, err, "container")
+	}
+	if got, err := c.Detect(ctx, strings.NewReader("input")); err != nil || got != "application/zip" {
+		t.Errorf("Detect = %q, %v, want %q", got, err, "application/zip")
+	}
+	if got, err := c.ServerVersion(ctx); err != nil || got != (tika.ParsedVersion{Major: 1, Minor: 21}) {
+		t.Errorf("ServerVersion = %v, %v, want 1.21.0", got, err)
+	}
+	m, err := c.MetaJSON(ctx, strings.NewReader("input"))
+	if err != nil {
+		t.Fatalf("MetaJSON got error: %v", err)
+	}
+	if got, want := m.GetAll("dc:creator"), []string{"Alice, Jr.", "Bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MetaJSON creator = %q, want %q", got, want)
+	}
+	meta, err := c.Meta(ctx, strings.NewReader("input"))
+	if err != nil {
+		t.Fatalf("Meta got error: %v", err)
+	}
+	if want := "\"dc:creator\",\"Alice, Jr.\",\"Bob\"\n"; !strings.Contains(meta, want) {
+		t.Errorf("Meta = %q, want it to contain %q", meta, want)
+	}
+	if got, err := c.MetaFieldValues(ctx, strings.NewReader("input"), "dc:creator"); err != nil || len(got) != 2 {
+		t.Errorf("MetaFieldValues = %q, %v, want 2 values", got, err)
+	}
+	if _, err := c.MetaField(ctx, strings.NewReader("input"), "missing"); err == nil {
+		t.Errorf("MetaField(missing) got no error")
+	}
+	docs, err := c.RecursiveMetadata(ctx, strings.NewReader("input"), tika.ContentText)
+	if err != nil {
+		t.Fatalf("RecursiveMetadata got error: %v", err)
+	}
+	if len(docs) != 2 || docs[0].Content() != "container" || docs[1].Content() != "embedded" {
+		t.Errorf("RecursiveMetadata = %v, want container and embedded documents", docs)
+	}
+	texts, err := c.ParseRecursive(ctx, strings.NewReader("input"))
+	if err != nil || !reflect.DeepEqual(texts, []string{"container", "embedded"}) {
+		t.Errorf("ParseRecursive = %q, %v, want container and embedded", texts, err)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Handle("/parsers/details", Response{Body: `{"name":"org.apache.tika.parser.DefaultParser"}`})
+	p, err := s.Client().Parsers(context.Background())
+	if err != nil {
+		t.Fatalf("Parsers got error: %v", err)
+	}
+	if p.Name != "org.apache.tika.parser.DefaultParser" {
+		t.Errorf("Parsers name = %q, want DefaultParser", p.Name)
+	}
+}
+
+func TestFail(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.Fail("/tika", 2, Response{StatusCode: http.StatusServiceUnavailable})
+	c := s.Client()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := c.Parse(ctx, strings.NewReader("input"))
+		var e *tika.Error
+		if !errors.As(err, &e) || e.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("Parse #%d got error %v, want 503", i, err)
+		}
+	}
+	if _, err := c.Version(ctx); err != nil {
+		t.Errorf("Version got error %v, want only /tika to fail", err)
+	}
+	if _, err := c.Parse(ctx, strings.NewReader("input")); err != nil {
+		t.Errorf("Parse after failures got error: %v", err)
+	}
+
+	s.Fail("", 1, Response{Delay: time.Minute})
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.Version(ctx); err == nil {
+		t.Errorf("Version with delayed response got no error")
+	}
+}
+
+func TestRequests(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client(tika.WithDefaultHeader("X-Test", "yes"))
+	if _, err := c.Parse(context.Background(), strings.NewReader("input"), tika.WithSkipEmbedded(true)); err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	reqs := s.Requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	r := reqs[0]
+	if r.Method != "PUT" || r.Path != "/tika" || string(r.Body) != "input" {
+		t.Errorf("request = %s %s %q, want PUT /tika \"input\"", r.Method, r.Path, r.Body)
+	}
+	if r.Header.Get("X-Test") != "yes" || r.Header.Get("X-Tika-Skip-Embedded") != "true" {
+		t.Errorf("request headers = %v, want X-Test and X-Tika-Skip-Embedded", r.Header)
+	}
+	s.Reset()
+	if got := s.Requests(); len(got) != 0 {
+		t.Errorf("Requests after Reset = %v, want none", got)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"strings"
+)
+
+// A Tracer starts spans for distributed tracing, such as OpenTelemetry
+// spans. go-tika doesn't depend on a tracing library; instead, implement
+// Tracer with a small adapter, for example:
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (o otelTracer) Start(ctx context.Context, name string) (context.Context, tika.Span) {
+//		ctx, span := o.t.Start(ctx, name)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ s trace.Span }
+//
+//	func (o otelSpan) SetAttribute(key string, value interface{}) {
+//		o.s.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//	}
+//
+//	func (o otelSpan) End(err error) {
+//		if err != nil {
+//			o.s.RecordError(err)
+//			o.s.SetStatus(codes.Error, err.Error())
+//		}
+//		o.s.End()
+//	}
+//
+// See WithTracer and WithServerTracer.
+type Tracer interface {
+	// Start starts a span called name as a child of the span in ctx, if
+	// any, and returns a context containing the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// A Span is a traced operation started by a Tracer.
+type Span interface {
+	// SetAttribute sets an attribute of the span. value is a string, int
+	// or int64.
+	SetAttribute(key string, value interface{})
+	// End ends the span. err is the error the operation failed with, or
+	// nil.
+	End(err error)
+}
+
+// Span attributes set by the Client and Server.
+const (
+	AttrHTTPMethod      = "http.request.method"
+	AttrHTTPStatusCode  = "http.response.status_code"
+	AttrHTTPRequestSize = "http.request.body.size"
+	AttrTikaEndpoint    = "tika.endpoint"
+	AttrTikaPath        = "tika.path"
+	AttrServerURL       = "tika.server.url"
+	AttrServerJAR       = "tika.server.jar"
+)
+
+// WithTracer traces every Client call with t. Each span lasts until the
+// response body has been read, including any retries.
+func WithTracer(t Tracer) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.tracer = t
+	}
+}
+
+// WithServerTracer traces starting and stopping the Server with t.
+func WithServerTracer(t Tracer) ServerOption {
+	return func(s *Server) {
+		s.tracer = t
+	}
+}
+
+// endpointOf returns the Tika endpoint of path, such as /meta for
+// /meta/dc:title.
+func endpointOf(path string) string {
+	if len(path) > 1 {
+		if i := strings.IndexByte(path[1:], '/'); i >= 0 {
+			return path[:i+1]
+		}
+	}
+	return path
+}
+
+// startSpan starts a span for a Server operation, if s has a tracer. The
+// returned function ends it.
+func (s *Server) startSpan(ctx context.Context, name string) (context.Context, func(error)) {
+	if s.tracer == nil {
+		return ctx, func(error) {}
+	}
+	ctx, span := s.tracer.Start(ctx, name)
+	span.SetAttribute(AttrServerURL, s.url)
+	span.SetAttribute(AttrServerJAR, s.jar)
+	return ctx, span.End
+}
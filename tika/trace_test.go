@@ -0,0 +1,155 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeTracer records the spans it starts.
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	name  string
+	attrs map[string]interface{}
+	ended bool
+	err   error
+}
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &fakeSpan{name: name, attrs: map[string]interface{}{}}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func (s *fakeSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+
+func (s *fakeSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+func TestWithTracer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/meta/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "test value")
+	}))
+	defer ts.Close()
+	tracer := &fakeTracer{}
+	c := NewClientWithOptions(ts.URL, WithTracer(tracer))
+
+	r, err := c.ParseReader(context.Background(), strings.NewReader("input"))
+	if err != nil {
+		t.Fatalf("ParseReader got error: %v", err)
+	}
+	span := tracer.spans[0]
+	if span.ended {
+		t.Errorf("span ended before the body was read")
+	}
+	ioutil.ReadAll(r)
+	r.Close()
+	want := &fakeSpan{
+		name: "tika PUT /tika",
+		attrs: map[string]interface{}{
+			AttrHTTPMethod:      "PUT",
+			AttrTikaEndpoint:    "/tika",
+			AttrTikaPath:        "/tika",
+			AttrHTTPRequestSize: 5,
+			AttrHTTPStatusCode:  200,
+		},
+		ended: true,
+	}
+	if !reflect.DeepEqual(span, want) {
+		t.Errorf("ParseReader span got %+v, want %+v", span, want)
+	}
+
+	if _, err := c.MetaField(context.Background(), nil, "missing"); err == nil {
+		t.Fatalf("MetaField got no error, want an error")
+	}
+	span = tracer.spans[1]
+	if span.name != "tika PUT /meta" || span.attrs[AttrTikaPath] != "/meta/missing" || span.attrs[AttrHTTPStatusCode] != 404 {
+		t.Errorf("MetaField span got %+v, want name %q, path %q and status 404", span, "tika PUT /meta", "/meta/missing")
+	}
+	if !span.ended || span.err == nil {
+		t.Errorf("MetaField span got ended %v with error %v, want ended with an error", span.ended, span.err)
+	}
+}
+
+func TestWithServerTracer(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skip("cannot find current test executable")
+	}
+	ts := bouncyServer(0)
+	defer ts.Close()
+	tsURL, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatalf("error creating test server: %v", err)
+	}
+	tracer := &fakeTracer{}
+	s, err := NewServer(path, tsURL.Port(), WithServerTracer(tracer))
+	if err != nil {
+		t.Fatalf("NewServer got error: %v", err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatalf("Start got error: %v", err)
+	}
+	s.Stop()
+	var names []string
+	for _, span := range tracer.spans {
+		names = append(names, span.name)
+		if !span.ended || span.attrs[AttrServerURL] != s.URL() || span.attrs[AttrServerJAR] != path {
+			t.Errorf("span got %+v, want it ended with the server URL and JAR", span)
+		}
+	}
+	if want := []string{"tika.server.start", "tika.server.stop"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Server spans got %q, want %q", names, want)
+	}
+}
+
+func TestEndpointOf(t *testing.T) {
+	tests := map[string]string{
+		"/tika":                  "/tika",
+		"/meta/dc:title":         "/meta",
+		"/translate/all/x/fr/en": "/translate",
+		"":                       "",
+		"/":                      "/",
+	}
+	for path, want := range tests {
+		if got := endpointOf(path); got != want {
+			t.Errorf("endpointOf(%q) got %q, want %q", path, got, want)
+		}
+	}
+}
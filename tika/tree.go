@@ -0,0 +1,134 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A DocumentNode is a document in the tree of a container and the documents
+// embedded in it, like an email with a zip attachment holding a report.
+type DocumentNode struct {
+	// Metadata is the metadata of the document, with its content in the
+	// XTIKAContent field.
+	Metadata Metadata
+	// Parent is the document this document is embedded in, or nil for the
+	// container.
+	Parent *DocumentNode
+	// Children are the documents directly embedded in this document, in the
+	// order Tika returned them.
+	Children []*DocumentNode
+}
+
+// Path returns the path of the document within the container, like
+// "/attachment.zip/report.pdf", or "" for the container itself.
+func (n *DocumentNode) Path() string {
+	return n.Metadata.EmbeddedPath()
+}
+
+// Name returns the name of the document within its parent, like
+// "report.pdf", or "" for the container.
+func (n *DocumentNode) Name() string {
+	p := n.Path()
+	return p[strings.LastIndex(p, "/")+1:]
+}
+
+// Depth returns the number of documents n is embedded in; 0 for the
+// container.
+func (n *DocumentNode) Depth() int {
+	d := 0
+	for p := n.Parent; p != nil; p = p.Parent {
+		d++
+	}
+	return d
+}
+
+// Walk calls fn for n and all of its descendants, parents before children,
+// stopping early if fn returns false.
+func (n *DocumentNode) Walk(fn func(n *DocumentNode) bool) bool {
+	if !fn(n) {
+		return false
+	}
+	for _, c := range n.Children {
+		if !c.Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildDocumentTree arranges docs, as returned by RecursiveMetadata, into a
+// tree using their XTIKAEmbeddedResourcePath and returns the container. A
+// document whose parent is missing from docs is attached to its closest
+// ancestor that is present, or to the container. BuildDocumentTree returns an
+// error if docs is empty.
+func BuildDocumentTree(docs []Metadata) (*DocumentNode, error) {
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no documents")
+	}
+	// The container is the document without a path, normally the first.
+	rootIndex := 0
+	for i, d := range docs {
+		if d.EmbeddedPath() == "" {
+			rootIndex = i
+			break
+		}
+	}
+	root := &DocumentNode{Metadata: docs[rootIndex]}
+	byPath := map[string]*DocumentNode{"": root}
+	nodes := make([]*DocumentNode, 0, len(docs))
+	for i, d := range docs {
+		if i == rootIndex {
+			continue
+		}
+		n := &DocumentNode{Metadata: d}
+		if _, ok := byPath[n.Path()]; !ok {
+			byPath[n.Path()] = n
+		}
+		nodes = append(nodes, n)
+	}
+	for _, n := range nodes {
+		parent := root
+		for p := n.Path(); ; {
+			i := strings.LastIndex(p, "/")
+			if i <= 0 {
+				break
+			}
+			p = p[:i]
+			if found, ok := byPath[p]; ok {
+				parent = found
+				break
+			}
+		}
+		n.Parent = parent
+		parent.Children = append(parent.Children, n)
+	}
+	return root, nil
+}
+
+// RecursiveMetadataTree is like RecursiveMetadata, but returns the documents
+// as a tree. See BuildDocumentTree.
+func (c *Client) RecursiveMetadataTree(ctx context.Context, input io.Reader, format ContentFormat, opts ...RequestOption) (*DocumentNode, error) {
+	docs, err := c.RecursiveMetadata(ctx, input, format, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return BuildDocumentTree(docs)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// embeddedDoc returns the metadata of a document at path.
+func embeddedDoc(path string) Metadata {
+	m := Metadata{XTIKAContent: {"content of " + path}}
+	if path != "" {
+		m[XTIKAEmbeddedResourcePath] = []string{path}
+	}
+	return m
+}
+
+// treeString returns the paths of the documents in the tree rooted at n,
+// indented by depth.
+func treeString(n *DocumentNode) string {
+	var lines []string
+	n.Walk(func(n *DocumentNode) bool {
+		lines = append(lines, strings.Repeat("  ", n.Depth())+"["+n.Name()+"]")
+		return true
+	})
+	return strings.Join(lines, "\n")
+}
+
+func TestBuildDocumentTree(t *testing.T) {
+	tests := []struct {
+		name string
+		docs []Metadata
+		want string
+	}{
+		{
+			name: "container only",
+			docs: []Metadata{embeddedDoc("")},
+			want: "[]",
+		},
+		{
+			name: "nested",
+			docs: []Metadata{
+				embeddedDoc(""),
+				embeddedDoc("/attachment.zip"),
+				embeddedDoc("/attachment.zip/report.docx"),
+				embeddedDoc("/attachment.zip/report.docx/image1.png"),
+				embeddedDoc("/attachment.zip/notes.txt"),
+				embeddedDoc("/signature.png"),
+			},
+			want: "[]\n  [attachment.zip]\n    [report.docx]\n      [image1.png]\n    [notes.txt]\n  [signature.png]",
+		},
+		{
+			name: "container last and a missing parent",
+			docs: []Metadata{
+				embeddedDoc("/a.zip/missing.tar/deep.txt"),
+				embeddedDoc("/a.zip"),
+				embeddedDoc(""),
+			},
+			want: "[]\n  [a.zip]\n    [deep.txt]",
+		},
+	}
+	for _, test := range tests {
+		root, err := BuildDocumentTree(test.docs)
+		if err != nil {
+			t.Fatalf("BuildDocumentTree(%s) got error: %v", test.name, err)
+		}
+		if got := treeString(root); got != test.want {
+			t.Errorf("BuildDocumentTree(%s) =\n%s\nwant\n%s", test.name, got, test.want)
+		}
+		root.Walk(func(n *DocumentNode) bool {
+			for _, c := range n.Children {
+				if c.Parent != n {
+					t.Errorf("BuildDocumentTree(%s): %q has parent %q, want %q", test.name, c.Path(), c.Parent.Path(), n.Path())
+				}
+			}
+			return true
+		})
+	}
+	if _, err := BuildDocumentTree(nil); err == nil {
+		t.Errorf("BuildDocumentTree(nil) got no error")
+	}
+}
+
+func TestDocumentNodeWalkStops(t *testing.T) {
+	root, err := BuildDocumentTree([]Metadata{embeddedDoc(""), embeddedDoc("/a"), embeddedDoc("/b")})
+	if err != nil {
+		t.Fatalf("BuildDocumentTree got error: %v", err)
+	}
+	var visited []string
+	root.Walk(func(n *DocumentNode) bool {
+		visited = append(visited, n.Path())
+		return n.Path() != "/a"
+	})
+	if want := []string{"", "/a"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("Walk visited %q, want %q", visited, want)
+	}
+}
+
+func TestRecursiveMetadataTree(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"X-TIKA:content":"mail"},{"X-TIKA:content":"zip","X-TIKA:embedded_resource_path":"/a.zip"},{"X-TIKA:content":"doc","X-TIKA:embedded_resource_path":"/a.zip/b.doc"}]`)
+	}))
+	defer ts.Close()
+	root, err := NewClient(nil, ts.URL).RecursiveMetadataTree(context.Background(), strings.NewReader("input"), ContentText)
+	if err != nil {
+		t.Fatalf("RecursiveMetadataTree got error: %v", err)
+	}
+	if len(root.Children) != 1 || len(root.Children[0].Children) != 1 {
+		t.Fatalf("RecursiveMetadataTree =\n%s\nwant a chain of three documents", treeString(root))
+	}
+	if got := root.Children[0].Children[0].Metadata.Content(); got != "doc" {
+		t.Errorf("innermost document content = %q, want %q", got, "doc")
+	}
+}
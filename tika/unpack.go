@@ -0,0 +1,90 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Files added by UnpackAll in addition to the embedded documents.
+const (
+	// UnpackTextFile contains the text of the container document.
+	UnpackTextFile = "__TEXT__"
+	// UnpackMetadataFile contains the metadata of the container document in
+	// CSV format.
+	UnpackMetadataFile = "__METADATA__"
+)
+
+var zipHeader = http.Header{"Accept": []string{"application/zip"}}
+
+// Unpack extracts the documents embedded in the given input, such as the
+// images in a DOCX file or the attachments of an email. The result maps the
+// name of each embedded document to its contents. If the error is not nil,
+// the result is undefined.
+func (c *Client) Unpack(ctx context.Context, input io.Reader, opts ...RequestOption) (map[string][]byte, error) {
+	return c.unpack(ctx, input, "/unpack", opts)
+}
+
+// UnpackAll is like Unpack, but the result also has the text and metadata of
+// the container in the UnpackTextFile and UnpackMetadataFile entries.
+func (c *Client) UnpackAll(ctx context.Context, input io.Reader, opts ...RequestOption) (map[string][]byte, error) {
+	return c.unpack(ctx, input, "/unpack/all", opts)
+}
+
+func (c *Client) unpack(ctx context.Context, input io.Reader, path string, opts []RequestOption) (map[string][]byte, error) {
+	body, err := c.call(ctx, input, "PUT", path, zipHeader, opts)
+	var e *Error
+	if errors.As(err, &e) && e.StatusCode == http.StatusNoContent {
+		// There are no embedded documents.
+		return map[string][]byte{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(zr.File))
+	for _, f := range zr.File {
+		if err := readZipFile(files, f); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// readZipFile reads f into files.
+func readZipFile(files map[string][]byte, f *zip.File) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	files[f.Name] = b
+	return nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"archive/zip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestUnpack(t *testing.T) {
+	files := map[string][]byte{
+		"image1.png":       []byte("png"),
+		"attachment.txt":   []byte("text"),
+		UnpackTextFile:     []byte("container text"),
+		UnpackMetadataFile: []byte(`"Content-Type","application/msword"`),
+	}
+	var path, accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, accept = r.URL.Path, r.Header.Get("Accept")
+		zw := zip.NewWriter(w)
+		for name, b := range files {
+			f, err := zw.Create(name)
+			if err != nil {
+				t.Errorf("error creating zip entry: %v", err)
+				return
+			}
+			f.Write(b)
+		}
+		zw.Close()
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+
+	tests := []struct {
+		name     string
+		unpack   func(c *Client) (map[string][]byte, error)
+		wantPath string
+	}{
+		{"Unpack", func(c *Client) (map[string][]byte, error) { return c.Unpack(context.Background(), nil) }, "/unpack"},
+		{"UnpackAll", func(c *Client) (map[string][]byte, error) { return c.UnpackAll(context.Background(), nil) }, "/unpack/all"},
+	}
+	for _, test := range tests {
+		got, err := test.unpack(c)
+		if err != nil {
+			t.Errorf("%s got error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, files) {
+			t.Errorf("%s got %q, want %q", test.name, got, files)
+		}
+		if path != test.wantPath || accept != "application/zip" {
+			t.Errorf("%s requested %q with Accept %q, want %q with Accept %q", test.name, path, accept, test.wantPath, "application/zip")
+		}
+		if _, err := test.unpack(errorClient); err == nil {
+			t.Errorf("%s got no error, want an error", test.name)
+		}
+	}
+}
+
+func TestUnpackNoContent(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+	got, err := NewClient(nil, ts.URL).Unpack(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Unpack got error: %v", err)
+	}
+	if got == nil || len(got) != 0 {
+		t.Errorf("Unpack got %q, want an empty map", got)
+	}
+}
+
+func TestUnpackBadZip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("not a zip file"))
+	}))
+	defer ts.Close()
+	if _, err := NewClient(nil, ts.URL).Unpack(context.Background(), nil); err == nil {
+		t.Errorf("Unpack got no error, want an error")
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"io"
+)
+
+// WithMaxUploadSize rejects documents larger than n bytes with an
+// *UploadTooLargeError. The size of strings, byte slices and readers that can
+// seek, like files, is checked before the request is sent. Other readers are
+// checked while they are sent, and the request is aborted once n bytes were
+// sent. See WithUploadTruncation to send the first n bytes instead.
+func WithMaxUploadSize(n int64) ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.maxUpload = n
+	}
+}
+
+// WithUploadTruncation makes a Client with WithMaxUploadSize send only the
+// first bytes of larger documents, up to the maximum size, rather than
+// rejecting them. Tika parses truncated documents as well as it can, which
+// works for text but may fail for other formats.
+func WithUploadTruncation() ClientOption {
+	return func(cfg *clientConfig) {
+		cfg.truncateUpload = true
+	}
+}
+
+// limitUpload returns input limited to c.maxUpload bytes, or an error if it
+// is known to be larger and c doesn't truncate uploads.
+func (c *Client) limitUpload(input io.Reader) (io.Reader, error) {
+	size := inputSize(input)
+	if size >= 0 && size <= c.maxUpload {
+		return input, nil
+	}
+	if c.truncateUpload {
+		return keepCloser(io.LimitReader(input, c.maxUpload), input), nil
+	}
+	if size >= 0 {
+		return nil, &UploadTooLargeError{Limit: c.maxUpload, Size: size}
+	}
+	return keepCloser(&maxSizeReader{r: input, limit: c.maxUpload, n: c.maxUpload}, input), nil
+}
+
+// keepCloser returns r with the Close method of orig, if it has one, so the
+// request still closes orig when it is done.
+func keepCloser(r, orig io.Reader) io.Reader {
+	c, ok := orig.(io.Closer)
+	if !ok {
+		return r
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, c}
+}
+
+// inputSize returns the number of bytes left to read from r, or -1 if it is
+// unknown.
+func inputSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err := v.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return end - cur
+	}
+	return -1
+}
+
+// maxSizeReader reads from r, returning an *UploadTooLargeError if it has
+// more than n bytes.
+type maxSizeReader struct {
+	r     io.Reader
+	limit int64
+	n     int64 // Bytes left before r is too large.
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	if m.n < 0 {
+		return 0, &UploadTooLargeError{Limit: m.limit, Size: -1}
+	}
+	// Read one byte more than allowed to detect a larger input.
+	if int64(len(p)) > m.n+1 {
+		p = p[:m.n+1]
+	}
+	n, err := m.r.Read(p)
+	m.n -= int64(n)
+	if m.n < 0 {
+		return 0, &UploadTooLargeError{Limit: m.limit, Size: -1}
+	}
+	return n, err
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxUploadSize(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.txt")
+	if err := ioutil.WriteFile(file, []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	open := func() io.Reader {
+		f, err := os.Open(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { f.Close() })
+		return f
+	}
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		input        func() io.Reader
+		want         string
+		wantSize     int64 // Size of the UploadTooLargeError, if not 0.
+		wantRequests int
+	}{
+		{
+			name:         "small enough",
+			opts:         []ClientOption{WithMaxUploadSize(10)},
+			input:        func() io.Reader { return strings.NewReader("0123456789") },
+			want:         "0123456789",
+			wantRequests: 1,
+		},
+		{
+			name:     "known size",
+			opts:     []ClientOption{WithMaxUploadSize(9)},
+			input:    func() io.Reader { return strings.NewReader("0123456789") },
+			wantSize: 10,
+		},
+		{
+			name:     "file",
+			opts:     []ClientOption{WithMaxUploadSize(5)},
+			input:    open,
+			wantSize: 10,
+		},
+		{
+			name:         "unknown size",
+			opts:         []ClientOption{WithMaxUploadSize(5)},
+			input:        func() io.Reader { return ioutil.NopCloser(strings.NewReader("0123456789")) },
+			wantSize:     -1,
+			wantRequests: -1, // The request may or may not reach the server.
+		},
+		{
+			name:         "unknown size, small enough",
+			opts:         []ClientOption{WithMaxUploadSize(10)},
+			input:        func() io.Reader { return ioutil.NopCloser(strings.NewReader("0123456789")) },
+			want:         "0123456789",
+			wantRequests: 1,
+		},
+		{
+			name:         "truncated",
+			opts:         []ClientOption{WithMaxUploadSize(4), WithUploadTruncation()},
+			input:        open,
+			want:         "0123",
+			wantRequests: 1,
+		},
+	}
+	for _, test := range tests {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Write(body)
+		}))
+		c := NewClientWithOptions(ts.URL, test.opts...)
+		got, err := c.Parse(context.Background(), test.input())
+		ts.Close()
+		if test.wantRequests >= 0 && requests != test.wantRequests {
+			t.Errorf("%s: server got %d requests, want %d", test.name, requests, test.wantRequests)
+		}
+		if test.wantSize == 0 {
+			if err != nil || got != test.want {
+				t.Errorf("%s: Parse = %q, %v, want %q", test.name, got, err, test.want)
+			}
+			continue
+		}
+		var e *UploadTooLargeError
+		if !errors.As(err, &e) || e.Size != test.wantSize || !errors.Is(err, ErrTooLarge) {
+			t.Errorf("%s: Parse got error %v, want an *UploadTooLargeError of size %d", test.name, err, test.wantSize)
+		}
+	}
+}
+
+func TestMaxUploadSizeNotRetried(t *testing.T) {
+	attempts := 0
+	hc := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		_, err := ioutil.ReadAll(r.Body)
+		return nil, err
+	})}
+	// Without buffering, the size is only checked while the body is sent.
+	c := NewClientWithOptions("http://tika", WithHTTPClient(hc), WithMaxUploadSize(1),
+		WithRetry(RetryPolicy{InitialBackoff: 1, MaxBufferSize: -1}))
+	_, err := c.Parse(context.Background(), struct{ io.Reader }{strings.NewReader("too large")})
+	var e *UploadTooLargeError
+	if !errors.As(err, &e) {
+		t.Errorf("Parse got error %v, want an *UploadTooLargeError", err)
+	}
+	if attempts != 1 {
+		t.Errorf("got %d attempts, want 1", attempts)
+	}
+}
+
+func TestMaxUploadSizeCached(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer ts.Close()
+	c := NewClientWithOptions(ts.URL, WithCache(NewMemoryCache(0)), WithMaxUploadSize(5))
+	var read int64
+	input := &countingReader{r: strings.NewReader(strings.Repeat("a", 1<<20)), n: &read}
+	_, err := c.Parse(context.Background(), input)
+	var e *UploadTooLargeError
+	if !errors.As(err, &e) {
+		t.Errorf("Parse got error %v, want an *UploadTooLargeError", err)
+	}
+	if read > 6 {
+		t.Errorf("read %d bytes of the input, want at most 6", read)
+	}
+	if requests != 0 {
+		t.Errorf("server got %d requests, want 0", requests)
+	}
+}
+
+// countingReader is an io.Reader that adds the number of bytes read to n.
+type countingReader struct {
+	r io.Reader
+	n *int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	*c.n += int64(n)
+	return n, err
+}
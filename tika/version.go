@@ -0,0 +1,73 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// A ParsedVersion is the version of a Tika Server, such as 1.21 or 2.9.2. See
+// Client.ServerVersion.
+type ParsedVersion struct {
+	Major, Minor, Patch int
+}
+
+var versionRE = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// ParseVersion parses the first version number in s, such as the "Apache Tika
+// 1.21" returned by Client.Version.
+func ParseVersion(s string) (ParsedVersion, error) {
+	m := versionRE.FindStringSubmatch(s)
+	if m == nil {
+		return ParsedVersion{}, fmt.Errorf("no version number in %q", s)
+	}
+	var v ParsedVersion
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+func (v ParsedVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or 1 if v is older than, the same as or newer than w.
+func (v ParsedVersion) Compare(w ParsedVersion) int {
+	return compareVersionNumbers([]int{v.Major, v.Minor, v.Patch}, []int{w.Major, w.Minor, w.Patch})
+}
+
+// AtLeast reports whether v is the same as or newer than the version s, for
+// example AtLeast("1.16"). It returns false if s is not a version number.
+func (v ParsedVersion) AtLeast(s string) bool {
+	w, err := ParseVersion(s)
+	return err == nil && v.Compare(w) >= 0
+}
+
+// ServerVersion returns the version of the Tika Server, parsed from Version.
+func (c *Client) ServerVersion(ctx context.Context, opts ...RequestOption) (ParsedVersion, error) {
+	s, err := c.Version(ctx, opts...)
+	if err != nil {
+		return ParsedVersion{}, err
+	}
+	return ParseVersion(s)
+}
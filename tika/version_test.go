@@ -0,0 +1,85 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ParsedVersion
+		wantErr bool
+	}{
+		{in: "Apache Tika 1.21", want: ParsedVersion{1, 21, 0}},
+		{in: "Apache Tika 2.9.2\n", want: ParsedVersion{2, 9, 2}},
+		{in: "3.0.0-BETA", want: ParsedVersion{3, 0, 0}},
+		{in: "Apache Tika", wantErr: true},
+	}
+	for _, test := range tests {
+		got, err := ParseVersion(test.in)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("ParseVersion(%q) got error %v, want error: %v", test.in, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseVersion(%q) got %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestParsedVersionAtLeast(t *testing.T) {
+	v := ParsedVersion{1, 21, 0}
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"1.16", true},
+		{"1.21", true},
+		{"1.21.0", true},
+		{"1.21.1", false},
+		{"2.0", false},
+		{"not a version", false},
+	}
+	for _, test := range tests {
+		if got := v.AtLeast(test.s); got != test.want {
+			t.Errorf("%v.AtLeast(%q) got %v, want %v", v, test.s, got, test.want)
+		}
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "Apache Tika 2.9.2")
+	}))
+	defer ts.Close()
+	got, err := NewClient(nil, ts.URL).ServerVersion(context.Background())
+	if err != nil {
+		t.Fatalf("ServerVersion got error: %v", err)
+	}
+	if want := (ParsedVersion{2, 9, 2}); got != want {
+		t.Errorf("ServerVersion got %v, want %v", got, want)
+	}
+	if _, err := errorClient.ServerVersion(context.Background()); err == nil {
+		t.Errorf("ServerVersion got no error, want an error")
+	}
+}
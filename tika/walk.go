@@ -0,0 +1,117 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+)
+
+// A WalkOption configures ParseFS and ParseDir.
+type WalkOption func(*walkConfig)
+
+type walkConfig struct {
+	include []string
+	exclude []string
+}
+
+// WithInclude only parses files matching one of patterns. A pattern matches
+// if it matches the slash-separated path of the file relative to the root,
+// or the file name, using the syntax of path.Match. For example, "*.pdf"
+// matches every PDF and "reports/*" every file directly in reports. By
+// default, every regular file is parsed.
+func WithInclude(patterns ...string) WalkOption {
+	return func(c *walkConfig) {
+		c.include = append(c.include, patterns...)
+	}
+}
+
+// WithExclude skips files and directories matching one of patterns, even if
+// they match WithInclude. Patterns match like those of WithInclude. For
+// example, ".git" skips every .git directory.
+func WithExclude(patterns ...string) WalkOption {
+	return func(c *walkConfig) {
+		c.exclude = append(c.exclude, patterns...)
+	}
+}
+
+// ParseFS walks the file tree rooted at root in fsys and parses every regular
+// file with b, as Run does. The ID of each result is the path of the file in
+// fsys. Errors walking the tree are reported as results for the directory
+// that could not be read. ParseFS returns an error if a pattern is invalid.
+func (b *Batch) ParseFS(ctx context.Context, fsys fs.FS, root string, opts ...WalkOption) (<-chan BatchResult, error) {
+	cfg := &walkConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	for _, p := range append(cfg.include, cfg.exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, err
+		}
+	}
+	inputs := make(chan BatchInput)
+	go func() {
+		defer close(inputs)
+		fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+			var in BatchInput
+			switch {
+			case err != nil:
+				in = BatchInput{Path: p, open: func() (io.Reader, error) { return nil, err }}
+			case p != root && matchAny(cfg.exclude, p):
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			case !d.Type().IsRegular():
+				return nil
+			case len(cfg.include) > 0 && !matchAny(cfg.include, p):
+				return nil
+			default:
+				in = BatchInput{Path: p, open: func() (io.Reader, error) { return fsys.Open(p) }}
+			}
+			select {
+			case inputs <- in:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		})
+	}()
+	return b.Run(ctx, inputs), nil
+}
+
+// ParseDir is like ParseFS for the directory tree rooted at dir. The ID of
+// each result is the path of the file relative to dir.
+func (b *Batch) ParseDir(ctx context.Context, dir string, opts ...WalkOption) (<-chan BatchResult, error) {
+	return b.ParseFS(ctx, os.DirFS(dir), ".", opts...)
+}
+
+// matchAny reports whether p or its base name matches one of patterns.
+func matchAny(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(p)); ok {
+			return true
+		}
+	}
+	return false
+}
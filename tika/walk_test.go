@@ -0,0 +1,146 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+)
+
+func TestParseFS(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		fmt.Fprintf(w, "parsed %s", body)
+	}))
+	defer ts.Close()
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)}, WithWorkers(2))
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+	fsys := fstest.MapFS{
+		"a.pdf":              {Data: []byte("a")},
+		"b.txt":              {Data: []byte("b")},
+		"docs/c.pdf":         {Data: []byte("c")},
+		"docs/old/d.pdf":     {Data: []byte("d")},
+		".git/objects/e.pdf": {Data: []byte("e")},
+	}
+	tests := []struct {
+		name string
+		opts []WalkOption
+		want map[string]string
+	}{
+		{
+			name: "all",
+			want: map[string]string{
+				"a.pdf":              "parsed a",
+				"b.txt":              "parsed b",
+				"docs/c.pdf":         "parsed c",
+				"docs/old/d.pdf":     "parsed d",
+				".git/objects/e.pdf": "parsed e",
+			},
+		},
+		{
+			name: "include and exclude",
+			opts: []WalkOption{WithInclude("*.pdf"), WithExclude(".git", "docs/old")},
+			want: map[string]string{
+				"a.pdf":      "parsed a",
+				"docs/c.pdf": "parsed c",
+			},
+		},
+		{
+			name: "path pattern",
+			opts: []WalkOption{WithInclude("docs/*")},
+			want: map[string]string{
+				"docs/c.pdf": "parsed c",
+			},
+		},
+	}
+	for _, test := range tests {
+		results, err := b.ParseFS(context.Background(), fsys, ".", test.opts...)
+		if err != nil {
+			t.Fatalf("ParseFS(%s) got error: %v", test.name, err)
+		}
+		got := make(map[string]string)
+		for res := range results {
+			if res.Err != nil {
+				t.Errorf("ParseFS(%s) %s got error: %v", test.name, res.ID, res.Err)
+			}
+			got[res.ID] = res.Text
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseFS(%s) = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	if _, err := b.ParseFS(context.Background(), fsys, ".", WithInclude("[")); err == nil {
+		t.Errorf("ParseFS with an invalid pattern got no error")
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer ts.Close()
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)})
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"one.txt", "sub/two.txt"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := b.ParseDir(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("ParseDir got error: %v", err)
+	}
+	var got []string
+	for res := range results {
+		if res.Err != nil || res.Text != res.ID {
+			t.Errorf("ParseDir %s = %q, %v, want the file name", res.ID, res.Text, res.Err)
+		}
+		got = append(got, res.ID)
+	}
+	sort.Strings(got)
+	if want := []string{"one.txt", "sub/two.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseDir = %q, want %q", got, want)
+	}
+
+	results, err = b.ParseDir(context.Background(), filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Fatalf("ParseDir(missing) got error: %v", err)
+	}
+	res, ok := <-results
+	if !ok || !os.IsNotExist(res.Err) {
+		t.Errorf("ParseDir(missing) = %+v, want a not exist error", res)
+	}
+}
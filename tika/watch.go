@@ -0,0 +1,187 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A Watcher monitors a directory tree and parses files as they are created
+// or modified. Create a Watcher with NewWatcher and run it with Run.
+//
+// The Watcher polls the directory, so it works on every platform and file
+// system, including network file systems. A file is parsed once its size
+// and modification time have not changed for the debounce period, so files
+// that are still being written are not parsed early.
+type Watcher struct {
+	b        *Batch
+	dir      string
+	interval time.Duration
+	debounce time.Duration
+	walk     walkConfig
+
+	files map[string]*watchedFile
+}
+
+// watchedFile is the state of a file seen by a Watcher.
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	changed time.Time // When modTime or size last changed.
+	handled bool      // Whether this version of the file was handled.
+}
+
+// A WatchOption configures a Watcher. See NewWatcher.
+type WatchOption func(*Watcher)
+
+// WithWatchInterval sets how often a Watcher scans the directory. The default
+// is 2 seconds.
+func WithWatchInterval(d time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.interval = d
+	}
+}
+
+// WithDebounce sets how long a file must be unchanged before a Watcher
+// parses it. The default is 1 second.
+func WithDebounce(d time.Duration) WatchOption {
+	return func(w *Watcher) {
+		w.debounce = d
+	}
+}
+
+// WithWatchFilter sets the files a Watcher parses, with WithInclude and
+// WithExclude.
+func WithWatchFilter(opts ...WalkOption) WatchOption {
+	return func(w *Watcher) {
+		for _, opt := range opts {
+			opt(&w.walk)
+		}
+	}
+}
+
+// NewWatcher creates a new Watcher that parses the files in the directory
+// tree rooted at dir with b. Files already in dir when Run is called are
+// parsed too.
+func NewWatcher(b *Batch, dir string, opts ...WatchOption) *Watcher {
+	w := &Watcher{
+		b:        b,
+		dir:      dir,
+		interval: 2 * time.Second,
+		debounce: time.Second,
+		files:    make(map[string]*watchedFile),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run watches the directory until ctx is done, and returns ctx.Err(). Run
+// calls handle with the result of parsing each new or modified file, one at a
+// time. The ID of each result is the path of the file relative to the
+// directory.
+//
+// Delivery is at least once: if handle returns an error, the file is parsed
+// and handled again on a later scan, until handle succeeds. A result with a
+// parse error is not retried if handle returns nil.
+func (w *Watcher) Run(ctx context.Context, handle func(BatchResult) error) error {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+	for {
+		w.process(ctx, w.scan(time.Now()), handle)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// scan updates w.files and returns the paths of the files that are ready to
+// be parsed at now.
+func (w *Watcher) scan(now time.Time) []string {
+	seen := make(map[string]bool)
+	var ready []string
+	filepath.Walk(w.dir, func(p string, info os.FileInfo, err error) error {
+		rel, relErr := filepath.Rel(w.dir, p)
+		if err != nil || relErr != nil || rel == "." {
+			// Try again on the next scan.
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if matchAny(w.walk.exclude, rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || len(w.walk.include) > 0 && !matchAny(w.walk.include, rel) {
+			return nil
+		}
+		seen[rel] = true
+		f, ok := w.files[rel]
+		if !ok || !f.modTime.Equal(info.ModTime()) || f.size != info.Size() {
+			f = &watchedFile{modTime: info.ModTime(), size: info.Size(), changed: now}
+			w.files[rel] = f
+		}
+		if !f.handled && now.Sub(f.changed) >= w.debounce {
+			ready = append(ready, rel)
+		}
+		return nil
+	})
+	for p := range w.files {
+		if !seen[p] {
+			delete(w.files, p)
+		}
+	}
+	return ready
+}
+
+// process parses the files at paths and handles the results.
+func (w *Watcher) process(ctx context.Context, paths []string, handle func(BatchResult) error) {
+	if len(paths) == 0 {
+		return
+	}
+	inputs := make(chan BatchInput)
+	go func() {
+		defer close(inputs)
+		for _, p := range paths {
+			name := filepath.Join(w.dir, filepath.FromSlash(p))
+			in := BatchInput{ID: p, open: func() (io.Reader, error) { return os.Open(name) }}
+			select {
+			case inputs <- in:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for res := range w.b.Run(ctx, inputs) {
+		if ctx.Err() != nil {
+			continue
+		}
+		if err := handle(res); err == nil {
+			// If the file changed while it was parsed, the next scan
+			// replaces this version and it is parsed again.
+			w.files[res.ID].handled = true
+		}
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newEchoBatch(t *testing.T) *Batch {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	t.Cleanup(ts.Close)
+	b, err := NewBatch([]*Client{NewClient(nil, ts.URL)})
+	if err != nil {
+		t.Fatalf("NewBatch got error: %v", err)
+	}
+	return b
+}
+
+func TestWatcherScan(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, mod time.Time) {
+		p := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now()
+	write("a.txt", "a", start)
+	write("skip.log", "skip", start)
+	w := NewWatcher(newEchoBatch(t), dir, WithDebounce(time.Second), WithWatchFilter(WithExclude("*.log")))
+
+	var handled []string
+	fail := true
+	handle := func(res BatchResult) error {
+		if res.Err != nil {
+			t.Errorf("%s got error: %v", res.ID, res.Err)
+		}
+		handled = append(handled, res.ID+":"+res.Text)
+		if fail {
+			fail = false
+			return errors.New("not now")
+		}
+		return nil
+	}
+	step := func(now time.Time, want ...string) {
+		t.Helper()
+		handled = nil
+		w.process(context.Background(), w.scan(now), handle)
+		if !reflect.DeepEqual(handled, want) {
+			t.Errorf("handled %q, want %q", handled, want)
+		}
+	}
+
+	// a.txt is new, so it waits for the debounce period.
+	step(start)
+	// The first delivery fails, so it is retried.
+	step(start.Add(time.Second), "a.txt:a")
+	step(start.Add(2*time.Second), "a.txt:a")
+	// Once handled, it isn't parsed again.
+	step(start.Add(3 * time.Second))
+	// Modifying it restarts the debounce period.
+	write("a.txt", "a2", start.Add(4*time.Second))
+	step(start.Add(4 * time.Second))
+	step(start.Add(5*time.Second), "a.txt:a2")
+	// Deleted files are forgotten.
+	if err := os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	step(start.Add(6 * time.Second))
+	if len(w.files) != 0 {
+		t.Errorf("watched files = %v, want none", w.files)
+	}
+}
+
+func TestWatcherRun(t *testing.T) {
+	dir := t.TempDir()
+	w := NewWatcher(newEchoBatch(t), dir, WithWatchInterval(10*time.Millisecond), WithDebounce(0))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "sub", "doc.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var got BatchResult
+	err := w.Run(ctx, func(res BatchResult) error {
+		got = res
+		cancel()
+		return nil
+	})
+	if err != context.Canceled {
+		t.Errorf("Run got error %v, want %v", err, context.Canceled)
+	}
+	if got.ID != "sub/doc.txt" || got.Text != "hello" {
+		t.Errorf("Run handled %+v, want sub/doc.txt", got)
+	}
+}
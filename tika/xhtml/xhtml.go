@@ -0,0 +1,174 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xhtml extracts the structure of the XHTML Tika returns from
+// tika.Client.ParseXHTML: its links, headings, images and anchors.
+//
+//	body, err := client.ParseXHTML(ctx, f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	doc, err := xhtml.Parse(strings.NewReader(body))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, h := range doc.Headings {
+//		fmt.Println(strings.Repeat("  ", h.Level-1) + h.Text)
+//	}
+package xhtml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// A Link is a hyperlink in a document.
+type Link struct {
+	Href string
+	Text string
+	// Rel is the relationship of the target to the document, if given.
+	Rel string
+}
+
+// A Heading is a section heading in a document.
+type Heading struct {
+	// Level is 1 for h1 through 6 for h6.
+	Level int
+	Text  string
+}
+
+// An Image is a reference to an image, such as one embedded in the document.
+type Image struct {
+	Src string
+	Alt string
+}
+
+// Document is the structure of an XHTML document.
+type Document struct {
+	Title string
+	// Links, Headings and Images are in document order.
+	Links    []Link
+	Headings []Heading
+	Images   []Image
+	// Anchors are the names of the <a name="..."> targets in the document,
+	// such as bookmarks.
+	Anchors []string
+	// Embedded are the names of the documents embedded in the document,
+	// which Tika marks with <div class="embedded" id="...">.
+	Embedded []string
+}
+
+// Parse reads the XHTML document in r. It is lenient, so that HTML which
+// isn't well-formed XML can be read as well.
+func Parse(r io.Reader) (*Document, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+
+	doc := &Document{}
+	// Text of the enclosing <title>, <a href> and <hN>, or nil outside them.
+	var title, link, heading *strings.Builder
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return doc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case name == "title":
+				title = &strings.Builder{}
+			case name == "a":
+				if n := attr(t, "name"); n != "" {
+					doc.Anchors = append(doc.Anchors, n)
+				}
+				if href := attr(t, "href"); href != "" {
+					doc.Links = append(doc.Links, Link{Href: href, Rel: attr(t, "rel")})
+					link = &strings.Builder{}
+				}
+			case name == "img":
+				doc.Images = append(doc.Images, Image{Src: attr(t, "src"), Alt: attr(t, "alt")})
+			case headingLevel(name) > 0:
+				doc.Headings = append(doc.Headings, Heading{Level: headingLevel(name)})
+				heading = &strings.Builder{}
+			}
+			if hasClass(attr(t, "class"), "embedded") {
+				if id := attr(t, "id"); id != "" {
+					doc.Embedded = append(doc.Embedded, id)
+				}
+			}
+		case xml.CharData:
+			for _, b := range []*strings.Builder{title, link, heading} {
+				if b != nil {
+					b.Write(t)
+				}
+			}
+		case xml.EndElement:
+			name := strings.ToLower(t.Name.Local)
+			switch {
+			case name == "title" && title != nil:
+				doc.Title = collapse(title.String())
+				title = nil
+			case name == "a" && link != nil:
+				doc.Links[len(doc.Links)-1].Text = collapse(link.String())
+				link = nil
+			case headingLevel(name) > 0 && heading != nil:
+				doc.Headings[len(doc.Headings)-1].Text = collapse(heading.String())
+				heading = nil
+			}
+		}
+	}
+}
+
+// attr returns the value of the named attribute of e, or "".
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if strings.EqualFold(a.Name.Local, name) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// hasClass reports whether the class attribute value classes includes c.
+func hasClass(classes, c string) bool {
+	for _, f := range strings.Fields(classes) {
+		if f == c {
+			return true
+		}
+	}
+	return false
+}
+
+// headingLevel returns the level of the heading element name, or 0 if it
+// isn't a heading.
+func headingLevel(name string) int {
+	if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+	return 0
+}
+
+// collapse trims s and replaces each run of white space in it with a space.
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xhtml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDoc = `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head>
+<meta name="Content-Type" content="application/pdf"/>
+<title>  The
+ Report</title>
+</head>
+<body>
+<h1>Introduction</h1>
+<p>See <a href="http://example.com/" rel="nofollow">the <b>example</b>
+site</a>&nbsp;and <a name="bookmark1"/>.</p>
+<h2>Results</h2>
+<img src="embedded:image1.png" alt="Chart"/>
+<div class="package-entry embedded" id="image1.png"/>
+<h2><a href="#bookmark1">Back</a></h2>
+</body>
+</html>`
+
+func TestParse(t *testing.T) {
+	got, err := Parse(strings.NewReader(testDoc))
+	if err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	want := &Document{
+		Title: "The Report",
+		Links: []Link{
+			{Href: "http://example.com/", Text: "the example site", Rel: "nofollow"},
+			{Href: "#bookmark1", Text: "Back"},
+		},
+		Headings: []Heading{
+			{Level: 1, Text: "Introduction"},
+			{Level: 2, Text: "Results"},
+			{Level: 2, Text: "Back"},
+		},
+		Images:   []Image{{Src: "embedded:image1.png", Alt: "Chart"}},
+		Anchors:  []string{"bookmark1"},
+		Embedded: []string{"image1.png"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	got, err := Parse(strings.NewReader(`<html><body><H1>Title<br>Line</H1><img src="a.png"></body></html>`))
+	if err != nil {
+		t.Fatalf("Parse got error: %v", err)
+	}
+	if want := []Heading{{Level: 1, Text: "TitleLine"}}; !reflect.DeepEqual(got.Headings, want) {
+		t.Errorf("Parse got headings %+v, want %+v", got.Headings, want)
+	}
+	if want := []Image{{Src: "a.png"}}; !reflect.DeepEqual(got.Images, want) {
+		t.Errorf("Parse got images %+v, want %+v", got.Images, want)
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var xmpHeader = http.Header{"Accept": []string{"application/rdf+xml"}}
+
+const rdfNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// MetaXMP parses the metadata from the given input and returns it as an XMP
+// packet, suitable for writing to a sidecar file. Use ParseXMP to read the
+// properties in the packet. If the error is not nil, the result is undefined.
+func (c *Client) MetaXMP(ctx context.Context, input io.Reader, opts ...RequestOption) (string, error) {
+	body, err := c.call(ctx, input, "PUT", "/meta", xmpHeader, opts)
+	return string(body), err
+}
+
+// ParseXMP reads the properties of the rdf:Description elements of an XMP
+// packet, such as one returned by MetaXMP. Each property is keyed by its
+// namespace prefix and name, as in "dc:title", and the items of an rdf:Bag,
+// rdf:Seq or rdf:Alt are separate values. Nested structures are flattened
+// into the text they contain.
+func ParseXMP(r io.Reader) (Metadata, error) {
+	m := Metadata{}
+	prefixes := map[string]string{}
+	key := func(n xml.Name) string {
+		if p, ok := prefixes[n.Space]; ok {
+			return p + ":" + n.Local
+		}
+		return n.Local
+	}
+	d := xml.NewDecoder(r)
+	var (
+		depth, descDepth int    // descDepth is 0 outside rdf:Description.
+		prop             string // The property being read, if any.
+		text             strings.Builder
+		items            int
+	)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			for _, a := range t.Attr {
+				if a.Name.Space == "xmlns" {
+					prefixes[a.Value] = a.Name.Local
+				}
+			}
+			switch {
+			case descDepth == 0 && t.Name.Space == rdfNamespace && t.Name.Local == "Description":
+				descDepth = depth
+				for _, a := range t.Attr {
+					if a.Name.Space != "xmlns" && a.Name.Space != rdfNamespace && a.Name.Space != "" {
+						m[key(a.Name)] = append(m[key(a.Name)], a.Value)
+					}
+				}
+			case descDepth > 0 && depth == descDepth+1:
+				prop, items = key(t.Name), 0
+				text.Reset()
+				for _, a := range t.Attr {
+					if a.Name.Space == rdfNamespace && a.Name.Local == "resource" {
+						m[prop] = append(m[prop], a.Value)
+						items++
+					}
+				}
+			case prop != "" && t.Name.Space == rdfNamespace && t.Name.Local == "li":
+				text.Reset()
+			}
+		case xml.CharData:
+			if prop != "" {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch {
+			case depth == descDepth:
+				descDepth = 0
+			case prop != "" && depth == descDepth+1:
+				if v := strings.TrimSpace(text.String()); items == 0 && v != "" {
+					m[prop] = append(m[prop], v)
+				}
+				prop = ""
+			case prop != "" && t.Name.Space == rdfNamespace && t.Name.Local == "li":
+				m[prop] = append(m[prop], strings.TrimSpace(text.String()))
+				text.Reset()
+				items++
+			}
+			depth--
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 Google Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tika
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testXMP = `<?xml version="1.0" encoding="UTF-8"?>
+<x:xmpmeta xmlns:x="adobe:ns:meta/">
+  <rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+    <rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:pdf="http://ns.adobe.com/pdf/1.3/" pdf:Producer="Skia/PDF">
+      <dc:title><rdf:Alt><rdf:li xml:lang="x-default">Report</rdf:li></rdf:Alt></dc:title>
+      <dc:creator>
+        <rdf:Seq>
+          <rdf:li>Ann</rdf:li>
+          <rdf:li>Bob</rdf:li>
+        </rdf:Seq>
+      </dc:creator>
+      <dc:format>application/pdf</dc:format>
+      <dc:source rdf:resource="http://example.com/report"/>
+    </rdf:Description>
+    <rdf:Description rdf:about="" xmlns:xmpTPg="http://ns.adobe.com/xap/1.0/t/pg/">
+      <xmpTPg:NPages>3</xmpTPg:NPages>
+    </rdf:Description>
+  </rdf:RDF>
+</x:xmpmeta>`
+
+func TestMetaXMP(t *testing.T) {
+	var accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		fmt.Fprint(w, testXMP)
+	}))
+	defer ts.Close()
+	c := NewClient(nil, ts.URL)
+	got, err := c.MetaXMP(context.Background(), strings.NewReader("pdf"))
+	if err != nil {
+		t.Fatalf("MetaXMP got error: %v", err)
+	}
+	if got != testXMP {
+		t.Errorf("MetaXMP got %q, want %q", got, testXMP)
+	}
+	if want := "application/rdf+xml"; accept != want {
+		t.Errorf("MetaXMP requested Accept %q, want %q", accept, want)
+	}
+	if _, err := errorClient.MetaXMP(context.Background(), nil); err == nil {
+		t.Errorf("MetaXMP got no error, want an error")
+	}
+}
+
+func TestParseXMP(t *testing.T) {
+	got, err := ParseXMP(strings.NewReader(testXMP))
+	if err != nil {
+		t.Fatalf("ParseXMP got error: %v", err)
+	}
+	want := Metadata{
+		"pdf:Producer":  {"Skia/PDF"},
+		"dc:title":      {"Report"},
+		"dc:creator":    {"Ann", "Bob"},
+		"dc:format":     {"application/pdf"},
+		"dc:source":     {"http://example.com/report"},
+		"xmpTPg:NPages": {"3"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseXMP got %q, want %q", got, want)
+	}
+	if _, err := ParseXMP(strings.NewReader("<a><b></a>")); err == nil {
+		t.Errorf("ParseXMP of bad XML got no error, want an error")
+	}
+}